@@ -0,0 +1,80 @@
+// Package ai makes decisions for computer players. It reads only the engine
+// state, so the same logic drives the windowed game and headless simulations.
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// Decide returns the actions the player the engine is waiting on should take
+// next, in order, using the strategy for that player's difficulty. Before
+// rolling it may return a lone trade offer or mortgage decision, and in debt
+// one step towards paying; call Decide again after applying it.
+func Decide(s *engine.GameState) []engine.Action {
+	p := s.Players[s.Actor()]
+	st := For(p.Difficulty)
+
+	switch s.Phase {
+	case engine.PhaseAuction:
+		if amount := nextBid(s, p, st.MaxBid(s, p, s.AuctionSpaceIdx)); amount > 0 {
+			return []engine.Action{engine.Bid{Amount: amount}}
+		}
+		return []engine.Action{engine.Pass{}}
+	case engine.PhaseTradeResponse:
+		if st.AcceptTrade(s, *s.PendingOffer) {
+			return []engine.Action{engine.AcceptTrade{}}
+		}
+		return []engine.Action{engine.DeclineTrade{}}
+	case engine.PhasePreRoll:
+		// One offer a turn, so a refusal does not bring the same offer back
+		if s.TradesProposed == 0 {
+			if offer, ok := st.ProposeTrade(s, p); ok {
+				if a := (engine.ProposeTrade{Offer: offer}); s.IsLegal(p.ID, a) {
+					return []engine.Action{a}
+				}
+			}
+		}
+		if a, ok := st.Mortgage(s, p); ok {
+			return []engine.Action{a}
+		}
+		// Build one house before rolling
+		var actions []engine.Action
+		if idx, ok := st.Build(s, p); ok {
+			actions = append(actions, engine.Build{Space: idx})
+		}
+		return append(actions, engine.RollDice{})
+	case engine.PhaseJailDecision:
+		return []engine.Action{st.Jail(s, p)}
+	case engine.PhaseBuyDecision:
+		if st.Buy(s, p, p.Position) {
+			return []engine.Action{engine.BuyProperty{}}
+		}
+		return []engine.Action{engine.DeclineBuy{}}
+	case engine.PhaseIncomeTax:
+		return []engine.Action{engine.PayIncomeTax{Percent: st.TaxPercent(s, p)}}
+	case engine.PhasePostAction:
+		return []engine.Action{engine.EndTurn{}}
+	case engine.PhaseDebt:
+		return []engine.Action{settleDebt(s, p)}
+	case engine.PhaseTransfer:
+		return []engine.Action{receiveMortgaged(s, p)}
+	}
+	return nil
+}
+
+// totalOwned counts properties owned by anyone, a rough measure of how far
+// the game has got.
+func totalOwned(s *engine.GameState) int {
+	n := 0
+	for _, pl := range s.Players {
+		n += len(pl.Properties)
+	}
+	return n
+}
+
+// cheaperTax reports whether 10% of p's net worth is less than the flat tax.
+func cheaperTax(s *engine.GameState, p *player.Player) bool {
+	return s.PlayerNetWorth(p.ID)/10 < config.IncomeTax
+}
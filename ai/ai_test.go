@@ -0,0 +1,26 @@
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// newGame starts a standard game between n AI players of difficulty d.
+func newGame(n int, d player.Difficulty) *engine.GameState {
+	var players []*player.Player
+	for i := 0; i < n; i++ {
+		p := player.NewPlayer(i, string(rune('A'+i)), true)
+		p.Difficulty = d
+		players = append(players, p)
+	}
+	return engine.New(players, rng.New(1), engine.StandardRules())
+}
+
+// own gives player id the lots at spaces.
+func own(s *engine.GameState, id int, spaces ...int) {
+	for _, idx := range spaces {
+		s.Players[id].AddProperty(idx)
+		s.Board.Properties[idx].OwnerID = id
+	}
+}
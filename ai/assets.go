@@ -0,0 +1,144 @@
+package ai
+
+import (
+	"sort"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/markov"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// rentFor is the rent due for landing on idx with a roll of roll, or 0 if
+// nobody collects there.
+func rentFor(s *engine.GameState, idx, roll int) int {
+	prop := s.Board.Properties[idx]
+	if !s.Board.IsProperty(idx) || prop.OwnerID < 0 || prop.Mortgaged {
+		return 0
+	}
+	if s.Rules.NoRentInJail && s.Players[prop.OwnerID].InJail {
+		return 0
+	}
+	if s.Board.Spaces[idx].Type == board.SpaceUtility {
+		// CalculateRent uses the dice last rolled
+		if s.CountOwnedUtilities(prop.OwnerID) == 2 {
+			return roll * 10
+		}
+		return roll * 4
+	}
+	return s.CalculateRent(idx)
+}
+
+// cashReserve is the worst rent p could owe an opponent where its next
+// roll lands, which it keeps in hand so one unlucky roll does not force a
+// sale.
+func cashReserve(s *engine.GameState, p *player.Player) int {
+	worst := 0
+	for roll := 2; roll <= 12; roll++ {
+		idx := (p.Position + roll) % config.SpaceCount
+		if owner := s.Board.Properties[idx].OwnerID; owner >= 0 && owner != p.ID {
+			worst = max(worst, rentFor(s, idx, roll))
+		}
+	}
+	return worst
+}
+
+// How much p stands to lose by mortgaging a lot, least first.
+const (
+	keepSpare       = iota // outside any group p has or nearly has
+	keepNearly             // in a group p lacks one lot of
+	keepMonopoly           // in a monopoly with no houses
+	keepDevelopment        // in a monopoly with houses on other lots
+)
+
+// keepRank says how much p stands to lose by mortgaging idx.
+func keepRank(s *engine.GameState, p *player.Player, idx int) int {
+	group := s.Board.Spaces[idx].Group
+	switch {
+	case s.HasMonopoly(p.ID, group):
+		for _, other := range s.Board.SpacesInGroup(group) {
+			if s.Board.Properties[other].Houses > 0 {
+				return keepDevelopment
+			}
+		}
+		return keepMonopoly
+	case almostMonopoly(s, p.ID, group):
+		return keepNearly
+	}
+	return keepSpare
+}
+
+// mortgageOrder lists the lots p can mortgage, the ones it can best spare
+// first: by keepRank, then by the least rent earned for the cash raised.
+func mortgageOrder(s *engine.GameState, p *player.Player) []int {
+	odds := markov.For(s.Board)
+	lots := s.MortgageableProperties(p.ID)
+	rank := make(map[int]int, len(lots))
+	earns := make(map[int]float64, len(lots))
+	for _, idx := range lots {
+		rank[idx] = keepRank(s, p, idx)
+		earns[idx] = odds.PerTurn[idx] * float64(rentFor(s, idx, 7)) / float64(max(s.MortgageValue(idx), 1))
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		if ri, rj := rank[lots[i]], rank[lots[j]]; ri != rj {
+			return ri < rj
+		}
+		return earns[lots[i]] < earns[lots[j]]
+	})
+	return lots
+}
+
+// houseToSell picks the house p gives up most cheaply: one from the group
+// that earns least on its houses.
+func houseToSell(s *engine.GameState, p *player.Player) (int, bool) {
+	yield := groupYields(s)
+	best, found := 0, false
+	for _, idx := range p.Properties {
+		if !s.CanSellHouseOnSpace(idx) {
+			continue
+		}
+		if !found || yield[s.Board.Spaces[idx].Group] < yield[s.Board.Spaces[best].Group] {
+			best, found = idx, true
+		}
+	}
+	return best, found
+}
+
+// unmortgageDeveloping pays off a mortgaged lot in one of p's monopolies,
+// which cannot be built on until the whole group is clear, if p still has
+// keep and its cash reserve afterwards.
+func unmortgageDeveloping(s *engine.GameState, p *player.Player, keep int) (engine.Action, bool) {
+	keep += cashReserve(s, p)
+	for _, idx := range p.Properties {
+		if s.Board.Properties[idx].Mortgaged && s.HasMonopoly(p.ID, s.Board.Spaces[idx].Group) &&
+			p.Money >= s.UnmortgageCost(idx)+keep {
+			return engine.Unmortgage{Space: idx}, true
+		}
+	}
+	return nil, false
+}
+
+// moveHouse sells a house past the third to pay for the house p would
+// build next, when that takes a group towards three and p is just short of
+// it. Rent jumps most with the third house, so a house beyond it earns
+// more elsewhere.
+func moveHouse(s *engine.GameState, p *player.Player, buffer int) (int, bool) {
+	buildable := buildOrder(s, p)
+	if len(buildable) == 0 || s.Board.Properties[buildable[0]].Houses >= 3 {
+		return 0, false
+	}
+	target := s.Board.Spaces[buildable[0]]
+	short := target.HouseCost + buffer - p.Money
+	if short <= 0 {
+		return 0, false
+	}
+	for _, idx := range p.Properties {
+		space := s.Board.Spaces[idx]
+		if space.Group != target.Group && s.Board.Properties[idx].Houses > 3 &&
+			space.HouseCost/2 >= short && s.IsLegal(p.ID, engine.SellHouse{Space: idx}) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+func TestCashReserve(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		position  int
+		theirs    []int // B's lots
+		mine      []int
+		houses    int // on Dark Blue 39
+		mortgaged bool
+		jailed    bool // B is in jail, where the house rules waive rent
+		want      int
+	}{
+		{name: "nothing in reach", position: 30, want: 0},
+		{name: "a house nine away", position: 30, theirs: []int{37, 39}, houses: 1, want: 200},
+		{name: "a hotel out of reach", position: 0, theirs: []int{37, 39}, houses: 5, want: 0},
+		{name: "its own lot", position: 30, mine: []int{37, 39}, houses: 1, want: 0},
+		{name: "a mortgaged lot", position: 30, theirs: []int{39}, mortgaged: true, want: 0},
+		{name: "an owner in jail", position: 30, theirs: []int{37, 39}, houses: 1, jailed: true, want: 0},
+		{name: "a utility on the likeliest roll", position: 5, theirs: []int{12}, want: 7 * 4},
+	} {
+		s := newGame(2, player.DifficultyNormal)
+		s.Rules.NoRentInJail = true
+		own(s, 1, tc.theirs...)
+		own(s, 0, tc.mine...)
+		s.Board.Properties[39].Houses = tc.houses
+		s.Board.Properties[39].Mortgaged = tc.mortgaged
+		s.Players[1].InJail = tc.jailed
+		p := s.Players[0]
+		p.Position = tc.position
+		if got := cashReserve(s, p); got != tc.want {
+			t.Errorf("%s: reserve %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+// ownEstate gives A a Brown monopoly, two of Light Blue, a railroad and
+// Dark Blue with a house on Mosquee Hassan II.
+func ownEstate(s *engine.GameState) {
+	own(s, 0, 1, 3, 5, 6, 8, 37, 39)
+	s.Board.Properties[39].Houses = 1
+}
+
+func TestKeepRank(t *testing.T) {
+	s := newGame(2, player.DifficultyNormal)
+	ownEstate(s)
+	for _, tc := range []struct {
+		space int
+		want  int
+	}{
+		{5, keepSpare},
+		{6, keepNearly},
+		{1, keepMonopoly},
+		{37, keepDevelopment},
+	} {
+		if got := keepRank(s, s.Players[0], tc.space); got != tc.want {
+			t.Errorf("space %d: rank %d, want %d", tc.space, got, tc.want)
+		}
+	}
+}
+
+func TestMortgageOrder(t *testing.T) {
+	s := newGame(2, player.DifficultyNormal)
+	ownEstate(s)
+	own(s, 0, 12)
+	// A lone railroad earns less on its mortgage than the utility, and
+	// Mosquee Hassan II cannot be mortgaged with a house on it
+	want := []int{5, 12, 6, 8, 1, 3, 37}
+	if got := mortgageOrder(s, s.Players[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+}
+
+func TestHouseToSell(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		houses map[int]int
+		want   int
+		ok     bool
+	}{
+		{name: "no houses"},
+		{name: "the only house", houses: map[int]int{39: 1}, want: 39, ok: true},
+		{name: "the group earning least", houses: map[int]int{1: 1, 39: 1}, want: 1, ok: true},
+		{name: "the fuller lot", houses: map[int]int{1: 1, 3: 2}, want: 3, ok: true},
+	} {
+		s := newGame(2, player.DifficultyNormal)
+		own(s, 0, 1, 3, 37, 39)
+		for idx, n := range tc.houses {
+			s.Board.Properties[idx].Houses = n
+		}
+		got, ok := houseToSell(s, s.Players[0])
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("%s: sells at %d (%t), want %d (%t)", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestUnmortgageDeveloping(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		lots  []int
+		money int
+		ok    bool
+	}{
+		{name: "a monopoly with cash to keep", lots: []int{1, 3}, money: 133, ok: true},
+		{name: "a monopoly short of keep", lots: []int{1, 3}, money: 132},
+		{name: "outside a monopoly", lots: []int{1}, money: 1500},
+	} {
+		s := newGame(2, player.DifficultyNormal)
+		own(s, 0, tc.lots...)
+		s.Board.Properties[1].Mortgaged = true
+		p := s.Players[0]
+		p.Money = tc.money
+		a, ok := unmortgageDeveloping(s, p, 100)
+		if ok != tc.ok {
+			t.Errorf("%s: unmortgages %t, want %t", tc.name, ok, tc.ok)
+		} else if ok && a != (engine.Unmortgage{Space: 1}) {
+			t.Errorf("%s: %+v, want Unmortgage of space 1", tc.name, a)
+		}
+	}
+}
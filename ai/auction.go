@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// auctionValue is what space is worth to p at auction, starting from its
+// list price. A lot that completes p's group is worth double and one that
+// stops a bidder still in the auction completing theirs half as much
+// again. Railroads gain with each one p holds, since each doubles the rent
+// of the others; a second utility is worth more than a first.
+func auctionValue(s *engine.GameState, p *player.Player, space int) int {
+	sp := s.Board.Spaces[space]
+	value := sp.Price
+	denial := false
+
+	switch sp.Type {
+	case board.SpaceProperty:
+		switch {
+		case almostMonopoly(s, p.ID, sp.Group):
+			value *= 2
+		case ownsInGroup(s, p.ID, sp.Group):
+			value = value * 5 / 4
+		}
+		denial = rivalBidder(s, p, func(id int) bool { return almostMonopoly(s, id, sp.Group) })
+	case board.SpaceRailroad:
+		value = value * (4 + s.CountOwnedRailroads(p.ID)) / 4
+		denial = rivalBidder(s, p, func(id int) bool {
+			return s.CountOwnedRailroads(id) == len(s.Board.RailroadSpaces())-1
+		})
+	case board.SpaceUtility:
+		if s.CountOwnedUtilities(p.ID) > 0 {
+			value = value * 3 / 2
+		} else {
+			value = value * 3 / 4
+		}
+	}
+	if denial {
+		value = max(value, sp.Price*3/2)
+	}
+	return value
+}
+
+// bidLimit caps value by what p can spend on space and still keep keep,
+// or its cash reserve if more, in hand. Unless the lot completes a group,
+// p also holds back the cost of bringing the groups it already has up to
+// three houses.
+func bidLimit(s *engine.GameState, p *player.Player, space, value, keep int) int {
+	keep = max(keep, cashReserve(s, p))
+	if !almostMonopoly(s, p.ID, s.Board.Spaces[space].Group) {
+		keep += buildReserve(s, p)
+	}
+	return min(value, p.Money-keep)
+}
+
+// buildReserve is the cost of the houses p still needs to reach three on
+// every lot of its monopolies.
+func buildReserve(s *engine.GameState, p *player.Player) int {
+	reserve := 0
+	for i, sp := range s.Board.Spaces {
+		if sp.Type == board.SpaceProperty && s.HasMonopoly(p.ID, sp.Group) {
+			reserve += sp.HouseCost * max(0, 3-s.Board.Properties[i].Houses)
+		}
+	}
+	return reserve
+}
+
+// nextBid is what p bids next in the auction towards limit, or 0 to pass.
+// It opens at half of limit rather than creeping up from nothing, and bids
+// straight past what every other bidder can afford when limit allows it,
+// which ends the auction. Otherwise it raises by the minimum.
+func nextBid(s *engine.GameState, p *player.Player, limit int) int {
+	limit = min(limit, p.Money)
+	bid := s.AuctionHighBid + config.BidIncrement
+	if bid > limit {
+		return 0
+	}
+
+	richest := 0
+	for _, other := range s.Players {
+		if other.ID != p.ID && s.AuctionActive[other.ID] && !other.Bankrupt {
+			richest = max(richest, other.Money)
+		}
+	}
+	if out := richest - config.BidIncrement + 1; out > bid && out <= limit {
+		return out
+	}
+	if open := limit / 2 / config.BidIncrement * config.BidIncrement; open > bid {
+		return open
+	}
+	return bid
+}
+
+// rivalBidder reports whether a bidder still in the auction other than p
+// matches threat and has the cash to outbid the standing bid.
+func rivalBidder(s *engine.GameState, p *player.Player, threat func(id int) bool) bool {
+	for _, other := range s.Players {
+		if other.ID != p.ID && !other.Bankrupt && s.AuctionActive[other.ID] &&
+			other.Money > s.AuctionHighBid && threat(other.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsInGroup reports whether playerID owns any lot of group.
+func ownsInGroup(s *engine.GameState, playerID int, group board.ColorGroup) bool {
+	for _, idx := range s.Board.SpacesInGroup(group) {
+		if s.Board.Properties[idx].OwnerID == playerID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// newAuction starts a game of three with every player still bidding on
+// the lot at space.
+func newAuction(space int) *engine.GameState {
+	s := newGame(3, player.DifficultyHard)
+	s.Phase = engine.PhaseAuction
+	s.AuctionSpaceIdx = space
+	s.AuctionHighBidder = -1
+	for i := range s.Players {
+		s.AuctionActive[i] = true
+	}
+	return s
+}
+
+func TestAuctionValue(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		space   int
+		mine    []int
+		theirs  []int // B's lots
+		passed  bool  // B has left the auction
+		highBid int
+		want    int
+	}{
+		{name: "list price", space: 39, want: 400},
+		{name: "completes a group", space: 39, mine: []int{37}, want: 800},
+		{name: "adds to a group", space: 8, mine: []int{6}, want: 125},
+		{name: "stops a rival", space: 39, theirs: []int{37}, want: 600},
+		{name: "a rival who has passed", space: 39, theirs: []int{37}, passed: true, want: 400},
+		{name: "a rival who cannot outbid", space: 39, theirs: []int{37}, highBid: 1500, want: 400},
+		{name: "a second railroad", space: 5, mine: []int{15}, want: 250},
+		{name: "stops the fourth railroad", space: 5, theirs: []int{15, 25, 35}, want: 300},
+		{name: "a first utility", space: 12, want: 112},
+		{name: "a second utility", space: 12, mine: []int{28}, want: 225},
+	} {
+		s := newAuction(tc.space)
+		own(s, 1, tc.theirs...)
+		own(s, 0, tc.mine...)
+		s.AuctionActive[1] = !tc.passed
+		s.AuctionHighBid = tc.highBid
+		if got := auctionValue(s, s.Players[0], tc.space); got != tc.want {
+			t.Errorf("%s: worth %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBidLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		space int
+		value int
+		mine  []int
+		rent  bool // A sits nine short of B's Dark Blue with a house
+		want  int
+	}{
+		{name: "the value", space: 39, value: 400, want: 400},
+		{name: "the cash less keep", space: 39, value: 1200, want: 900},
+		{name: "houses still to build", space: 39, value: 1200, mine: []int{1, 3}, want: 600},
+		{name: "a group it completes", space: 39, value: 1200, mine: []int{1, 3, 37}, want: 900},
+		{name: "rent in reach", space: 5, value: 1200, rent: true, want: 800},
+	} {
+		s := newAuction(tc.space)
+		own(s, 0, tc.mine...)
+		p := s.Players[0]
+		p.Money = 1000
+		if tc.rent {
+			own(s, 1, 37, 39)
+			s.Board.Properties[39].Houses = 1
+			p.Position = 30
+		}
+		if got := bidLimit(s, p, tc.space, tc.value, 100); got != tc.want {
+			t.Errorf("%s: limit %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNextBid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		money   int
+		limit   int
+		highBid int
+		rivals  [2]int // B's and C's cash
+		passed  bool   // B has left the auction
+		want    int
+	}{
+		{name: "opens at half the limit", money: 1500, limit: 400, rivals: [2]int{1500, 1500}, want: 200},
+		{name: "raises by the minimum", money: 1500, limit: 400, highBid: 250, rivals: [2]int{1500, 1500}, want: 260},
+		{name: "passes past the limit", money: 1500, limit: 400, highBid: 395, rivals: [2]int{1500, 1500}, want: 0},
+		{name: "outbids every rival", money: 1500, limit: 400, rivals: [2]int{150, 100}, want: 141},
+		{name: "ignores a rival who passed", money: 1500, limit: 400, rivals: [2]int{1500, 100}, passed: true, want: 91},
+		{name: "no more than its cash", money: 300, limit: 1000, rivals: [2]int{1500, 1500}, want: 150},
+	} {
+		s := newAuction(39)
+		s.Players[0].Money = tc.money
+		s.Players[1].Money, s.Players[2].Money = tc.rivals[0], tc.rivals[1]
+		s.AuctionActive[1] = !tc.passed
+		s.AuctionHighBid = tc.highBid
+		if got := nextBid(s, s.Players[0], tc.limit); got != tc.want {
+			t.Errorf("%s: bid %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"sort"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/markov"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// groupYields rates each colour group for building: the rent three houses
+// on each lot earn per opponent turn, for each MAD the lots and houses
+// cost. Landing odds come from the board itself, so a different layout or
+// deck changes the order.
+func groupYields(s *engine.GameState) map[board.ColorGroup]float64 {
+	odds := markov.For(s.Board)
+	rent := map[board.ColorGroup]float64{}
+	cost := map[board.ColorGroup]int{}
+	for idx, space := range s.Board.Spaces {
+		if space.Type == board.SpaceProperty {
+			rent[space.Group] += odds.PerTurn[idx] * float64(space.Rent[3])
+			cost[space.Group] += space.Price + 3*space.HouseCost
+		}
+	}
+	yields := map[board.ColorGroup]float64{}
+	for g, c := range cost {
+		if c > 0 {
+			yields[g] = rent[g] / float64(c)
+		}
+	}
+	return yields
+}
+
+// bestBuild picks where p should build one house, keeping at least buffer
+// in hand.
+func bestBuild(s *engine.GameState, p *player.Player, buffer int) (int, bool) {
+	buildable := buildOrder(s, p)
+	if len(buildable) == 0 {
+		return 0, false
+	}
+
+	// Build one house on the best candidate
+	idx := buildable[0]
+	if p.Money >= s.Board.Spaces[idx].HouseCost+buffer {
+		return idx, true
+	}
+	return 0, false
+}
+
+// buildOrder lists where p can build, best first. It prioritises
+// high-yield groups and focuses on reaching 3 houses on one group before
+// starting another (the ROI sweet spot).
+func buildOrder(s *engine.GameState, p *player.Player) []int {
+	buildable := s.BuildableProperties(p.ID)
+	yield := groupYields(s)
+
+	// Sort buildable properties: prefer groups with fewer houses (to reach 3 first),
+	// then by group yield (best return on houses first).
+	sort.Slice(buildable, func(i, j int) bool {
+		si := s.Board.Spaces[buildable[i]]
+		sj := s.Board.Spaces[buildable[j]]
+		hi := s.Board.Properties[buildable[i]].Houses
+		hj := s.Board.Properties[buildable[j]].Houses
+
+		// Prefer the group that hasn't reached 3 houses yet
+		iUnder3 := hi < 3
+		jUnder3 := hj < 3
+		if iUnder3 != jUnder3 {
+			return iUnder3
+		}
+		// Then by group yield
+		if yi, yj := yield[si.Group], yield[sj.Group]; yi != yj {
+			return yi > yj
+		}
+		// Then by fewest houses (even building)
+		return hi < hj
+	})
+	return buildable
+}
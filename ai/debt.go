@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// settleDebt raises cash one step at a time until the debt can be paid,
+// giving up what p can best spare first: lots outside its monopolies, then
+// unbuilt monopolies, then houses from the groups earning least on them,
+// and only then the lots of groups it has built on. The engine declares
+// the player bankrupt once nothing is left to raise, so conceding here is
+// only a fallback.
+func settleDebt(s *engine.GameState, p *player.Player) engine.Action {
+	if a := (engine.PayDebt{}); s.IsLegal(p.ID, a) {
+		return a
+	}
+
+	lots := mortgageOrder(s, p)
+	if len(lots) > 0 && keepRank(s, p, lots[0]) < keepDevelopment {
+		return engine.Mortgage{Space: lots[0]}
+	}
+	if idx, ok := houseToSell(s, p); ok {
+		return engine.SellHouse{Space: idx}
+	}
+	if len(lots) > 0 {
+		return engine.Mortgage{Space: lots[0]}
+	}
+	return engine.DeclareBankruptcy{}
+}
+
+// receiveMortgaged pays off a mortgaged lot received straight away when p
+// can spare the cash, sooner if it completes a group that can then be built
+// on; otherwise it pays the interest and keeps the lot mortgaged.
+func receiveMortgaged(s *engine.GameState, p *player.Player) engine.Action {
+	space := s.Transfers[0].Space
+	reserve := 500
+	if s.HasMonopoly(p.ID, s.Board.Spaces[space].Group) {
+		reserve = tradeReserve
+	}
+	return engine.ReceiveMortgaged{Unmortgage: p.Money >= s.UnmortgageCost(space)+reserve}
+}
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// easy plays like a beginner: it buys on a whim, bids low, builds late and
+// takes trades without noticing what they give away.
+type easy struct{}
+
+func (easy) Buy(s *engine.GameState, p *player.Player, space int) bool {
+	// Passes on three properties in ten it could afford
+	return p.Money >= s.Board.Spaces[space].Price+50 && s.Rand.Intn(10) < 7
+}
+
+func (easy) MaxBid(s *engine.GameState, p *player.Player, space int) int {
+	return min(s.Board.Spaces[space].Price/2, p.Money-100)
+}
+
+func (easy) Build(s *engine.GameState, p *player.Player) (int, bool) {
+	// Builds wherever it can first, and only with plenty to spare
+	buildable := s.BuildableProperties(p.ID)
+	if len(buildable) == 0 || p.Money < s.Board.Spaces[buildable[0]].HouseCost+400 {
+		return 0, false
+	}
+	return buildable[0], true
+}
+
+func (easy) Mortgage(s *engine.GameState, p *player.Player) (engine.Action, bool) {
+	return nil, false
+}
+
+func (easy) Jail(s *engine.GameState, p *player.Player) engine.Action {
+	// Always hurries out, even when jail is the safest place to be
+	if p.GetOutOfJailCards > 0 {
+		return engine.UseJailCard{}
+	}
+	if p.Money >= s.Rules.JailFine {
+		return engine.PayJailFine{}
+	}
+	return engine.RollDice{}
+}
+
+func (easy) TaxPercent(s *engine.GameState, p *player.Player) bool {
+	return false
+}
+
+func (easy) ProposeTrade(s *engine.GameState, p *player.Player) (engine.TradeOffer, bool) {
+	// Never thinks to ask
+	return engine.TradeOffer{}, false
+}
+
+func (easy) AcceptTrade(s *engine.GameState, offer engine.TradeOffer) bool {
+	// Face value only, and happy to lose a little on the deal
+	received := offer.OfferedMoney
+	given := offer.WantedMoney
+	for _, idx := range offer.OfferedProps {
+		received += s.Board.Spaces[idx].Price
+	}
+	for _, idx := range offer.WantedProps {
+		given += s.Board.Spaces[idx].Price
+	}
+	return received >= given*8/10
+}
@@ -0,0 +1,252 @@
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// Cash hard keeps back when buying and building.
+const (
+	hardBuyBuffer   = 50
+	hardBuildBuffer = 50
+)
+
+// hardBuildChoices is how many places to build hard weighs by rollouts.
+const hardBuildChoices = 3
+
+// hard plays to win. Before buying, building, bidding, answering a trade or
+// leaving jail it plays each option forward in rollouts, a fixed number and
+// then more for as long as ThinkTime allows, and takes the one that leaves
+// it best off; the rules of thumb below order the options and break ties.
+// It also mortgages spare lots and moves houses to build sooner, keeps
+// enough cash for the worst rent ahead and drives a harder bargain in its
+// own offers.
+type hard struct{}
+
+func (h hard) Buy(s *engine.GameState, p *player.Player, space int) bool {
+	if p.Money < s.Board.Spaces[space].Price {
+		return false
+	}
+	return rolloutYes(s, p, h.buyByRule(s, p, space),
+		[]engine.Action{engine.BuyProperty{}}, []engine.Action{engine.DeclineBuy{}})
+}
+
+func (hard) buyByRule(s *engine.GameState, p *player.Player, space int) bool {
+	price := s.Board.Spaces[space].Price
+	if contested(s, p, space) {
+		return p.Money >= price
+	}
+	return p.Money >= price+max(hardBuyBuffer, cashReserve(s, p))
+}
+
+// MaxBid is settled one bid at a time: hard makes its next bid if that
+// plays out better than passing, and otherwise stops at the standing bid.
+func (h hard) MaxBid(s *engine.GameState, p *player.Player, space int) int {
+	limit := bidLimit(s, p, space, auctionValue(s, p, space), hardBuyBuffer)
+	if s.Phase != engine.PhaseAuction {
+		return limit
+	}
+	bid := nextBid(s, p, limit)
+	rule := bid > 0
+	if !rule {
+		bid = s.AuctionHighBid + config.BidIncrement
+	}
+	if bid > p.Money {
+		return limit
+	}
+	if rolloutYes(s, p, rule, []engine.Action{engine.Bid{Amount: bid}}, []engine.Action{engine.Pass{}}) {
+		return max(limit, bid)
+	}
+	return s.AuctionHighBid
+}
+
+func (hard) Build(s *engine.GameState, p *player.Player) (int, bool) {
+	rule, ok := bestBuild(s, p, max(hardBuildBuffer, cashReserve(s, p)))
+	if s.Phase != engine.PhasePreRoll {
+		return rule, ok
+	}
+
+	// The first few places to build, against not building this turn
+	roll := []engine.Action{engine.RollDice{}}
+	var choices [][]engine.Action
+	var spaces []int
+	if !ok {
+		choices = append(choices, roll)
+	}
+	for _, idx := range buildOrder(s, p) {
+		if len(spaces) == hardBuildChoices {
+			break
+		}
+		if p.Money >= s.Board.Spaces[idx].HouseCost {
+			choices = append(choices, []engine.Action{engine.Build{Space: idx}, engine.RollDice{}})
+			spaces = append(spaces, idx)
+		}
+	}
+	if ok {
+		choices = append(choices, roll)
+	}
+	if len(spaces) == 0 {
+		return 0, false
+	}
+
+	i := bestChoice(s, p, choices)
+	switch {
+	case !ok && i == 0, ok && i == len(spaces):
+		return 0, false
+	case !ok:
+		return spaces[i-1], true
+	}
+	return spaces[i], true
+}
+
+func (hard) Mortgage(s *engine.GameState, p *player.Player) (engine.Action, bool) {
+	// Lots in a monopoly come back first: the group cannot be built on
+	// while any of it is mortgaged
+	if a, ok := unmortgageDeveloping(s, p, hardBuildBuffer); ok {
+		return a, true
+	}
+
+	reserve := cashReserve(s, p)
+	buildable := buildOrder(s, p)
+	if len(buildable) == 0 {
+		// Nothing to build, so spare cash buys back the other lots
+		for _, idx := range p.Properties {
+			if s.Board.Properties[idx].Mortgaged && p.Money >= s.UnmortgageCost(idx)+reserve+500 {
+				return engine.Unmortgage{Space: idx}, true
+			}
+		}
+		return nil, false
+	}
+
+	buffer := max(hardBuildBuffer, reserve)
+	if idx, ok := moveHouse(s, p, buffer); ok {
+		return engine.SellHouse{Space: idx}, true
+	}
+
+	// Short of the next house: raise it from the lots it can best spare,
+	// outside any group that is complete or nearly so, if they can cover it
+	cost := s.Board.Spaces[buildable[0]].HouseCost
+	if p.Money >= cost+buffer {
+		return nil, false
+	}
+	var spare []int
+	raised := p.Money
+	for _, idx := range mortgageOrder(s, p) {
+		if keepRank(s, p, idx) == keepSpare {
+			spare = append(spare, idx)
+			raised += s.MortgageValue(idx)
+		}
+	}
+	if len(spare) > 0 && raised >= cost+buffer {
+		return engine.Mortgage{Space: spare[0]}, true
+	}
+	return nil, false
+}
+
+func (h hard) Jail(s *engine.GameState, p *player.Player) engine.Action {
+	rule := h.jailByRule(s, p)
+	choices := [][]engine.Action{{rule}}
+	for _, a := range []engine.Action{engine.UseJailCard{}, engine.PayJailFine{}, engine.RollDice{}} {
+		if a != rule && s.IsLegal(p.ID, a) {
+			choices = append(choices, []engine.Action{a})
+		}
+	}
+	if i := bestChoice(s, p, choices); i > 0 {
+		return choices[i][0]
+	}
+	return rule
+}
+
+func (hard) jailByRule(s *engine.GameState, p *player.Player) engine.Action {
+	// Out early to buy; once the board is mostly owned jail is the safest
+	// place to sit out other players' rents
+	if totalOwned(s) > 16 {
+		return engine.RollDice{}
+	}
+	if p.GetOutOfJailCards > 0 {
+		return engine.UseJailCard{}
+	}
+	if p.Money >= s.Rules.JailFine+hardBuyBuffer {
+		return engine.PayJailFine{}
+	}
+	return engine.RollDice{}
+}
+
+func (hard) TaxPercent(s *engine.GameState, p *player.Player) bool {
+	return cheaperTax(s, p)
+}
+
+func (hard) ProposeTrade(s *engine.GameState, p *player.Player) (engine.TradeOffer, bool) {
+	// A finished group is worth paying well over list price for
+	return monopolyOffer(s, p, 200)
+}
+
+func (h hard) AcceptTrade(s *engine.GameState, offer engine.TradeOffer) bool {
+	rule := h.acceptByRule(s, offer)
+	if s.Phase != engine.PhaseTradeResponse {
+		return rule
+	}
+	return rolloutYes(s, s.Players[offer.ToPlayer], rule,
+		[]engine.Action{engine.AcceptTrade{}}, []engine.Action{engine.DeclineTrade{}})
+}
+
+func (hard) acceptByRule(s *engine.GameState, offer engine.TradeOffer) bool {
+	aiID := offer.ToPlayer
+	received := offer.OfferedMoney
+	given := offer.WantedMoney
+	completesOwn := false
+
+	for _, idx := range offer.OfferedProps {
+		space := s.Board.Spaces[idx]
+		value := tradeValue(s, idx)
+		if almostMonopoly(s, aiID, space.Group) {
+			value *= 2
+			completesOwn = true
+		}
+		received += value
+	}
+	for _, idx := range offer.WantedProps {
+		space := s.Board.Spaces[idx]
+		value := tradeValue(s, idx)
+		if wouldCompleteMonopoly(s, offer.FromPlayer, space.Group) {
+			// Only worth it as a swap that completes a group in return
+			if !completesOwn {
+				return false
+			}
+			value *= 2
+		}
+		if almostMonopoly(s, aiID, space.Group) {
+			value = value * 15 / 10
+		}
+		given += value
+	}
+
+	// Wants the better of the deal
+	return received >= given*11/10
+}
+
+// rolloutYes settles a yes-or-no choice for p by rollouts of the actions
+// for each answer, keeping rule's answer on a tie.
+func rolloutYes(s *engine.GameState, p *player.Player, rule bool, yes, no []engine.Action) bool {
+	choices := [][]engine.Action{no, yes}
+	if rule {
+		choices = [][]engine.Action{yes, no}
+	}
+	return (bestChoice(s, p, choices) == 0) == rule
+}
+
+// contested reports whether owning space would complete p's group or stop
+// an opponent completing theirs.
+func contested(s *engine.GameState, p *player.Player, space int) bool {
+	group := s.Board.Spaces[space].Group
+	if almostMonopoly(s, p.ID, group) {
+		return true
+	}
+	for _, other := range s.Players {
+		if other.ID != p.ID && !other.Bankrupt && almostMonopoly(s, other.ID, group) {
+			return true
+		}
+	}
+	return false
+}
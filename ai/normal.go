@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// normal is the original AI: it keeps a cash buffer, bids a little below
+// what a lot is worth to it and guards against handing an opponent a
+// monopoly.
+type normal struct{}
+
+func (normal) Buy(s *engine.GameState, p *player.Player, space int) bool {
+	price := s.Board.Spaces[space].Price
+	return p.ShouldBuy(price, totalOwned(s)) && p.Money-price >= cashReserve(s, p)
+}
+
+func (normal) MaxBid(s *engine.GameState, p *player.Player, space int) int {
+	// Up to 90% of its worth, keeping 100 in hand
+	return bidLimit(s, p, space, auctionValue(s, p, space)*9/10, 100)
+}
+
+func (normal) Build(s *engine.GameState, p *player.Player) (int, bool) {
+	// Use a lower buffer when cash-rich
+	buffer := player.AIBuildBuffer
+	if p.Money > 1000 {
+		buffer = player.AIBuildBufferLow
+	}
+	return bestBuild(s, p, max(buffer, cashReserve(s, p)))
+}
+
+func (normal) Mortgage(s *engine.GameState, p *player.Player) (engine.Action, bool) {
+	return unmortgageDeveloping(s, p, player.AIBuildBuffer)
+}
+
+func (normal) Jail(s *engine.GameState, p *player.Player) engine.Action {
+	// Late game if >20 total properties owned
+	lateGame := totalOwned(s) > 20
+
+	if p.GetOutOfJailCards > 0 && !lateGame {
+		return engine.UseJailCard{}
+	} else if lateGame {
+		// Late game: prefer staying in jail (safe from rent)
+		// Unless forced out after max turns
+		return engine.RollDice{}
+	} else if p.Money >= s.Rules.JailFine+200 {
+		return engine.PayJailFine{}
+	}
+	return engine.RollDice{}
+}
+
+func (normal) TaxPercent(s *engine.GameState, p *player.Player) bool {
+	return cheaperTax(s, p)
+}
+
+func (normal) ProposeTrade(s *engine.GameState, p *player.Player) (engine.TradeOffer, bool) {
+	return monopolyOffer(s, p, 150)
+}
+
+func (normal) AcceptTrade(s *engine.GameState, offer engine.TradeOffer) bool {
+	aiID := offer.ToPlayer
+	received := offer.OfferedMoney
+	given := offer.WantedMoney
+	completesOwn := false
+
+	for _, idx := range offer.OfferedProps {
+		space := s.Board.Spaces[idx]
+		value := tradeValue(s, idx)
+		// Weight higher if receiving this property would complete AI's monopoly
+		if almostMonopoly(s, aiID, space.Group) {
+			value = value * 18 / 10 // 1.8x
+			completesOwn = true
+		}
+		received += value
+	}
+	for _, idx := range offer.WantedProps {
+		space := s.Board.Spaces[idx]
+		value := tradeValue(s, idx)
+
+		// Reject if this would complete opponent's monopoly, unless it is
+		// a swap that completes ours too
+		if wouldCompleteMonopoly(s, offer.FromPlayer, space.Group) && !completesOwn {
+			return false
+		}
+		// Weight higher if AI almost has a monopoly in that group (reluctant to give up)
+		if almostMonopoly(s, aiID, space.Group) {
+			value = value * 15 / 10 // 1.5x
+		}
+		given += value
+	}
+
+	return received >= given
+}
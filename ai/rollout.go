@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"time"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/markov"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// Limits on the rollouts hard plays before a decision. Each choice gets
+// the same number of rollouts with the same dice, so the comparison is
+// fair. Every decision plays at least rolloutGames, and more while
+// ThinkTime lasts.
+const (
+	rolloutGames    = 24   // per choice, however little time there is
+	rolloutMaxGames = 400  // per choice, however much time there is
+	rolloutRounds   = 8    // rounds played ahead in each
+	rolloutSteps    = 2000 // actions, in case a rollout stalls
+
+	rolloutRentRounds = 10 // rounds of rent counted after a rollout ends
+)
+
+// ThinkTime is how long the Hard AI may go on playing rollouts once each
+// choice has had rolloutGames. The default of zero stops there, so a game
+// replays the same way from its seed on any machine, as the simulator
+// needs; with time to spare, slower machines play fewer and choices may
+// differ. Set it before any game starts.
+var ThinkTime time.Duration
+
+// bestChoice plays each choice of actions for p forward from s a number
+// of times, with every seat played by the normal strategy, and returns
+// the index of the choice with the best average outcome. A rollout scores
+// 1 if p wins, 0 if p goes bankrupt and p's share of the players' net
+// worth otherwise. Ties go to the earlier choice.
+func bestChoice(s *engine.GameState, p *player.Player, choices [][]engine.Action) int {
+	if len(choices) < 2 {
+		return len(choices) - 1
+	}
+	deadline := time.Now().Add(ThinkTime)
+	scores := make([]float64, len(choices))
+	for n := 0; n < rolloutMaxGames && (n < rolloutGames || time.Now().Before(deadline)); n++ {
+		// Seeded from the game without drawing from it
+		seed := int64(s.Rand.State() ^ uint64(n+1)*0x9e3779b97f4a7c15)
+		for i, actions := range choices {
+			scores[i] += rollout(s, p.ID, actions, seed)
+		}
+	}
+
+	best := 0
+	for i, score := range scores {
+		if score > scores[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// rollout applies actions for player id to a copy of s, plays the copy on
+// and scores how it ended for id.
+func rollout(s *engine.GameState, id int, actions []engine.Action, seed int64) float64 {
+	r := rng.New(seed)
+	c := s.Clone(r)
+	// The order of the cards is hidden, so any order will do
+	c.Board.ChanceDeck.Shuffle()
+	c.Board.CommunityDeck.Shuffle()
+	for _, pl := range c.Players {
+		pl.Difficulty = player.DifficultyNormal
+	}
+
+	for _, a := range actions {
+		if err := c.Apply(id, a); err != nil {
+			return 0
+		}
+	}
+	end := c.Round + rolloutRounds
+	for steps := 0; !c.IsOver() && c.Round < end && steps < rolloutSteps; {
+		next := Decide(c)
+		if len(next) == 0 {
+			break
+		}
+		for _, a := range next {
+			if c.Apply(c.Actor(), a) != nil {
+				return outcome(c, id)
+			}
+			steps++
+		}
+	}
+	return outcome(c, id)
+}
+
+// outcome scores the game for player id: 1 for a win, 0 once bankrupt,
+// otherwise their share of the worth still in play.
+func outcome(s *engine.GameState, id int) float64 {
+	if s.Players[id].Bankrupt {
+		return 0
+	}
+	if s.IsOver() {
+		return 1
+	}
+	odds := markov.For(s.Board)
+	alive := s.AlivePlayers()
+	total, own := 0.0, 0.0
+	for _, pl := range alive {
+		w := worth(s, odds, pl, len(alive)-1)
+		total += w
+		if pl.ID == id {
+			own = w
+		}
+	}
+	if total <= 0 {
+		return 0
+	}
+	return own / total
+}
+
+// worth is p's net worth plus the rent its lots can expect from opponents
+// over the next rolloutRentRounds rounds. A short rollout ends before
+// houses pay for themselves, and without the rent to come it would rate
+// building as a loss.
+func worth(s *engine.GameState, odds *markov.Odds, p *player.Player, opponents int) float64 {
+	rent := 0.0
+	for _, idx := range p.Properties {
+		rent += odds.PerTurn[idx] * float64(rentFor(s, idx, 7))
+	}
+	return float64(s.PlayerNetWorth(p.ID)) + rent*float64(opponents*rolloutRentRounds)
+}
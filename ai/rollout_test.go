@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+func TestBestChoice(t *testing.T) {
+	roll := []engine.Action{engine.RollDice{}}
+	illegal := []engine.Action{engine.Mortgage{Space: 39}}
+	for _, tc := range []struct {
+		name    string
+		choices [][]engine.Action
+		want    int
+	}{
+		{"no choice", nil, -1},
+		{"one choice", [][]engine.Action{illegal}, 0},
+		{"illegal first", [][]engine.Action{illegal, roll}, 1},
+		{"illegal last", [][]engine.Action{roll, illegal}, 0},
+	} {
+		s := newGame(2, player.DifficultyHard)
+		if got := bestChoice(s, s.Players[0], tc.choices); got != tc.want {
+			t.Errorf("%s: chose %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBestChoiceReplays(t *testing.T) {
+	s := newGame(3, player.DifficultyHard)
+	s.Players[0].Position = 3
+	s.Phase = engine.PhaseBuyDecision
+	state := s.Rand.State()
+	choices := [][]engine.Action{{engine.DeclineBuy{}}, {engine.BuyProperty{}}}
+
+	first := bestChoice(s, s.Players[0], choices)
+	for i := 0; i < 3; i++ {
+		if got := bestChoice(s, s.Players[0], choices); got != first {
+			t.Fatalf("chose %d, then %d from the same game", first, got)
+		}
+	}
+	if s.Rand.State() != state || s.Players[0].Money != 1500 || s.Board.Properties[3].OwnerID != -1 {
+		t.Error("playing ahead changed the game")
+	}
+}
+
+func TestThinkTime(t *testing.T) {
+	defer func(d time.Duration) { ThinkTime = d }(ThinkTime)
+	ThinkTime = 30 * time.Millisecond
+
+	s := newGame(2, player.DifficultyHard)
+	start := time.Now()
+	got := bestChoice(s, s.Players[0], [][]engine.Action{{engine.Mortgage{Space: 39}}, {engine.RollDice{}}})
+	if got != 1 {
+		t.Errorf("chose %d, want the legal roll", got)
+	}
+	if elapsed := time.Since(start); elapsed < ThinkTime {
+		t.Errorf("stopped after %v of %v", elapsed, ThinkTime)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		setup func(s *engine.GameState)
+		want  float64
+	}{
+		{"even", func(s *engine.GameState) {}, 0.5},
+		{"bankrupt", func(s *engine.GameState) { s.Players[0].Bankrupt = true }, 0},
+		{"won", func(s *engine.GameState) { s.Players[1].Bankrupt = true }, 1},
+		{"three to one", func(s *engine.GameState) { s.Players[1].Money = 500 }, 0.75},
+	} {
+		s := newGame(2, player.DifficultyHard)
+		tc.setup(s)
+		if got := outcome(s, 0); got != tc.want {
+			t.Errorf("%s: scored %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
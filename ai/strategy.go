@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// Strategy makes the decisions for an AI player. Each method is only asked
+// when that decision is due, for the player p who has to make it.
+type Strategy interface {
+	// Buy reports whether p should buy the property at space at list price.
+	Buy(s *engine.GameState, p *player.Player, space int) bool
+	// MaxBid is the most p will pay for space at auction.
+	MaxBid(s *engine.GameState, p *player.Player, space int) int
+	// Build picks a property for p's next house, if any.
+	Build(s *engine.GameState, p *player.Player) (int, bool)
+	// Mortgage returns a Mortgage, Unmortgage or SellHouse for p to make
+	// before rolling, if any.
+	Mortgage(s *engine.GameState, p *player.Player) (engine.Action, bool)
+	// Jail chooses how p tries to leave jail: UseJailCard, PayJailFine or
+	// RollDice.
+	Jail(s *engine.GameState, p *player.Player) engine.Action
+	// TaxPercent reports whether p pays income tax as a percentage of net
+	// worth rather than the flat amount.
+	TaxPercent(s *engine.GameState, p *player.Player) bool
+	// ProposeTrade returns an offer for p to make before rolling, if any.
+	ProposeTrade(s *engine.GameState, p *player.Player) (engine.TradeOffer, bool)
+	// AcceptTrade reports whether the recipient should accept offer.
+	AcceptTrade(s *engine.GameState, offer engine.TradeOffer) bool
+}
+
+// For returns the strategy for an AI difficulty.
+func For(d player.Difficulty) Strategy {
+	switch d {
+	case player.DifficultyEasy:
+		return easy{}
+	case player.DifficultyHard:
+		return hard{}
+	default:
+		return normal{}
+	}
+}
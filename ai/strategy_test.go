@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+func TestFor(t *testing.T) {
+	for _, tc := range []struct {
+		d    player.Difficulty
+		want Strategy
+	}{
+		{player.DifficultyEasy, easy{}},
+		{player.DifficultyNormal, normal{}},
+		{player.DifficultyHard, hard{}},
+		{player.Difficulty(99), normal{}},
+	} {
+		if got := For(tc.d); got != tc.want {
+			t.Errorf("%s plays as %T, want %T", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestDecideLegal(t *testing.T) {
+	for _, tc := range []struct {
+		d      player.Difficulty
+		rounds int
+	}{
+		{player.DifficultyEasy, 60},
+		{player.DifficultyNormal, 60},
+		{player.DifficultyHard, 6},
+	} {
+		s := newGame(3, tc.d)
+		for steps := 0; !s.IsOver() && s.Round <= tc.rounds; {
+			actions := Decide(s)
+			if len(actions) == 0 {
+				t.Fatalf("%s: nothing to do in %s", tc.d, s.Phase)
+			}
+			for _, a := range actions {
+				if err := s.Apply(s.Actor(), a); err != nil {
+					t.Fatalf("%s: %T in %s: %v", tc.d, a, s.Phase, err)
+				}
+				if steps++; steps > 20000 {
+					t.Fatalf("%s: game stalled in round %d", tc.d, s.Round)
+				}
+			}
+		}
+	}
+}
+
+func TestJail(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		d     player.Difficulty
+		money int
+		cards int
+		late  bool
+		want  engine.Action
+	}{
+		{"easy with a card", player.DifficultyEasy, 1500, 1, false, engine.UseJailCard{}},
+		{"easy with the fine", player.DifficultyEasy, 60, 0, true, engine.PayJailFine{}},
+		{"easy short of the fine", player.DifficultyEasy, 40, 0, false, engine.RollDice{}},
+		{"normal with a card", player.DifficultyNormal, 1500, 1, false, engine.UseJailCard{}},
+		{"normal with cash", player.DifficultyNormal, 1500, 0, false, engine.PayJailFine{}},
+		{"normal short of cash", player.DifficultyNormal, 200, 0, false, engine.RollDice{}},
+		{"normal late", player.DifficultyNormal, 1500, 1, true, engine.RollDice{}},
+	} {
+		s := newGame(2, tc.d)
+		p := s.Players[0]
+		p.Money, p.GetOutOfJailCards = tc.money, tc.cards
+		if tc.late {
+			own(s, 1, 1, 3, 5, 6, 8, 9, 11, 12, 13, 14, 15, 16, 18, 19, 21, 23, 24, 25, 26, 27, 28)
+		}
+		if got := For(tc.d).Jail(s, p); got != tc.want {
+			t.Errorf("%s: %T, want %T", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTaxPercent(t *testing.T) {
+	for _, tc := range []struct {
+		d     player.Difficulty
+		money int
+		want  bool
+	}{
+		{player.DifficultyEasy, 500, false},
+		{player.DifficultyNormal, 500, true},
+		{player.DifficultyNormal, 2500, false},
+		{player.DifficultyHard, 500, true},
+	} {
+		s := newGame(2, tc.d)
+		s.Players[0].Money = tc.money
+		if got := For(tc.d).TaxPercent(s, s.Players[0]); got != tc.want {
+			t.Errorf("%s with %d: percent %t, want %t", tc.d, tc.money, got, tc.want)
+		}
+	}
+}
+
+func TestBuy(t *testing.T) {
+	for _, tc := range []struct {
+		d     player.Difficulty
+		money int
+		want  bool
+	}{
+		{player.DifficultyEasy, 100, false},
+		{player.DifficultyNormal, 100, false},
+		{player.DifficultyNormal, 1500, true},
+		{player.DifficultyHard, 100, false},
+	} {
+		s := newGame(2, tc.d)
+		s.Players[0].Money = tc.money
+		if got := For(tc.d).Buy(s, s.Players[0], 39); got != tc.want {
+			t.Errorf("%s with %d: buy %t, want %t", tc.d, tc.money, got, tc.want)
+		}
+	}
+}
+
+func TestAcceptTrade(t *testing.T) {
+	// A owns Brown 3 and Light Blue 6 and 8; B owns Brown 1 and Light Blue 9
+	for _, tc := range []struct {
+		name  string
+		offer engine.TradeOffer
+		want  [3]bool // easy, normal, hard
+	}{
+		{"list price for a railroad", engine.TradeOffer{WantedProps: []int{5}, OfferedMoney: 200},
+			[3]bool{true, true, false}},
+		{"a railroad below list", engine.TradeOffer{WantedProps: []int{5}, OfferedMoney: 160},
+			[3]bool{true, false, false}},
+		{"cash to complete their group", engine.TradeOffer{WantedProps: []int{3}, OfferedMoney: 300},
+			[3]bool{true, false, false}},
+		{"a swap completing both groups", engine.TradeOffer{WantedProps: []int{3}, OfferedProps: []int{9}},
+			[3]bool{true, true, true}},
+	} {
+		for i, d := range []player.Difficulty{player.DifficultyEasy, player.DifficultyNormal, player.DifficultyHard} {
+			s := newGame(2, d)
+			own(s, 0, 3, 5, 6, 8)
+			own(s, 1, 1, 9)
+			offer := tc.offer
+			offer.FromPlayer, offer.ToPlayer = 1, 0
+			if got := For(d).AcceptTrade(s, offer); got != tc.want[i] {
+				t.Errorf("%s: %s accepts %t, want %t", tc.name, d, got, tc.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"sort"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+const (
+	tradeCadence = 3   // rounds between offers to the same partner
+	tradeReserve = 200 // cash kept back after paying for a lot
+)
+
+// monopolyOffer looks for the last lot p needs to complete a colour group
+// and asks its owner for it. The offer is a swap for a lot that completes
+// one of the owner's groups in turn, if p has one, and otherwise cash at
+// cashPercent of the list price. Each partner is asked at most once every
+// tradeCadence rounds, so a declined offer is not repeated every turn.
+func monopolyOffer(s *engine.GameState, p *player.Player, cashPercent int) (engine.TradeOffer, bool) {
+	groups := []board.ColorGroup{
+		board.GroupBrown, board.GroupLightBlue, board.GroupPink, board.GroupOrange,
+		board.GroupRed, board.GroupYellow, board.GroupGreen, board.GroupDarkBlue,
+	}
+	yield := groupYields(s)
+	sort.Slice(groups, func(i, j int) bool { return yield[groups[i]] > yield[groups[j]] })
+
+	for _, group := range groups {
+		if !almostMonopoly(s, p.ID, group) {
+			continue
+		}
+		for _, idx := range s.Board.SpacesInGroup(group) {
+			owner := s.Board.Properties[idx].OwnerID
+			if owner == p.ID || owner < 0 || s.Players[owner].Bankrupt ||
+				!s.CanTradeProperty(idx) || (s.Round+owner)%tradeCadence != 0 {
+				continue
+			}
+
+			offer := engine.TradeOffer{FromPlayer: p.ID, ToPlayer: owner, WantedProps: []int{idx}}
+			if swap, ok := swapFor(s, p, owner, group); ok {
+				offer.OfferedProps = []int{swap}
+				return offer, true
+			}
+			cash := tradeValue(s, idx) * cashPercent / 100
+			interest, _ := s.TransferCost(offer.WantedProps)
+			if p.Money-cash-interest >= tradeReserve {
+				offer.OfferedMoney = cash
+				return offer, true
+			}
+		}
+	}
+	return engine.TradeOffer{}, false
+}
+
+// swapFor finds a lot of p's, outside the group p is completing, that would
+// complete one of owner's groups.
+func swapFor(s *engine.GameState, p *player.Player, owner int, completing board.ColorGroup) (int, bool) {
+	for _, idx := range p.Properties {
+		group := s.Board.Spaces[idx].Group
+		if group != completing && s.CanTradeProperty(idx) && almostMonopoly(s, owner, group) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// tradeValue is what a lot is worth changing hands: its price, less the
+// cost of paying off the mortgage if it has one.
+func tradeValue(s *engine.GameState, idx int) int {
+	value := s.Board.Spaces[idx].Price
+	if s.Board.Properties[idx].Mortgaged {
+		value -= s.UnmortgageCost(idx)
+	}
+	return value
+}
+
+// almostMonopoly returns true if the player owns all but one property in a group.
+func almostMonopoly(s *engine.GameState, playerID int, group board.ColorGroup) bool {
+	if group == board.GroupNone {
+		return false
+	}
+	spaces := s.Board.SpacesInGroup(group)
+	if len(spaces) == 0 {
+		return false
+	}
+	owned := 0
+	for _, idx := range spaces {
+		if s.Board.Properties[idx].OwnerID == playerID {
+			owned++
+		}
+	}
+	return owned == len(spaces)-1
+}
+
+// wouldCompleteMonopoly checks if giving a player a property would complete their monopoly.
+func wouldCompleteMonopoly(s *engine.GameState, playerID int, group board.ColorGroup) bool {
+	if group == board.GroupNone {
+		return false
+	}
+	spaces := s.Board.SpacesInGroup(group)
+	owned := 0
+	for _, idx := range spaces {
+		if s.Board.Properties[idx].OwnerID == playerID {
+			owned++
+		}
+	}
+	return owned >= len(spaces)-1
+}
@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+func TestMonopolyOffer(t *testing.T) {
+	// A owns Brown 1 and asks B, who owns Brown 3, for the other half
+	for _, tc := range []struct {
+		name      string
+		round     int
+		money     int
+		swap      bool // B owns Light Blue 6 and 8, A owns Light Blue 9
+		mortgaged bool // Brown 3 is mortgaged
+		want      engine.TradeOffer
+		ok        bool
+	}{
+		{name: "cash at half again the list price", round: 2, money: 1500,
+			want: engine.TradeOffer{WantedProps: []int{3}, OfferedMoney: 90}, ok: true},
+		{name: "a swap before cash", round: 2, money: 1500, swap: true,
+			want: engine.TradeOffer{WantedProps: []int{3}, OfferedProps: []int{9}}, ok: true},
+		{name: "a mortgaged lot less its payoff", round: 2, money: 1500, mortgaged: true,
+			want: engine.TradeOffer{WantedProps: []int{3}, OfferedMoney: 40}, ok: true},
+		{name: "just above the reserve", round: 2, money: 290,
+			want: engine.TradeOffer{WantedProps: []int{3}, OfferedMoney: 90}, ok: true},
+		{name: "below the reserve", round: 2, money: 289},
+		{name: "between offers", round: 3, money: 1500},
+		{name: "the next offer", round: 5, money: 1500,
+			want: engine.TradeOffer{WantedProps: []int{3}, OfferedMoney: 90}, ok: true},
+	} {
+		s := newGame(2, player.DifficultyNormal)
+		s.Round = tc.round
+		own(s, 0, 1)
+		own(s, 1, 3)
+		if tc.swap {
+			own(s, 0, 9)
+			own(s, 1, 6, 8)
+		}
+		s.Board.Properties[3].Mortgaged = tc.mortgaged
+		p := s.Players[0]
+		p.Money = tc.money
+
+		got, ok := monopolyOffer(s, p, 150)
+		if ok != tc.ok {
+			t.Errorf("%s: offer %t, want %t", tc.name, ok, tc.ok)
+			continue
+		}
+		if ok {
+			tc.want.FromPlayer, tc.want.ToPlayer = 0, 1
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: offer %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMonopolyOfferNoGroup(t *testing.T) {
+	s := newGame(2, player.DifficultyNormal)
+	s.Round = 2
+	own(s, 0, 6)
+	own(s, 1, 8, 9)
+	if offer, ok := monopolyOffer(s, s.Players[0], 150); ok {
+		t.Errorf("offer %+v without a group one lot short", offer)
+	}
+}
@@ -0,0 +1,218 @@
+// Package api lets scripts watch and drive a game over HTTP. The server
+// only listens on the loopback interface and is off unless asked for.
+//
+//	GET  /state   the game as JSON, with the actions the engine is waiting for
+//	POST /action  apply an action, e.g. {"type":"Bid","data":{"Amount":60}};
+//	              "player" picks who acts, otherwise whoever the engine waits on
+//
+// Handlers never touch the game themselves: they queue work that runs the
+// next time the game loop calls Poll, so the game needs no locking.
+//
+// A web page open in the user's browser can also reach the loopback
+// interface, so requests naming another host (DNS rebinding) or carrying
+// an Origin header are refused, and POST /action must be sent as JSON,
+// which a page cannot do without the browser asking first.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/lan"
+)
+
+// DefaultPort is where the server listens when the address names no port.
+const DefaultPort = "7778"
+
+// waitTimeout bounds how long a request waits for the game loop.
+const waitTimeout = 5 * time.Second
+
+// Game is what the server observes and drives. Its methods are only
+// called from Poll.
+type Game interface {
+	// Engine returns the game in progress, or nil in the menus.
+	Engine() *engine.GameState
+	// DialogName names the dialog on screen; headless games have none.
+	DialogName() string
+	// Act makes a move for player id, or says why it cannot.
+	Act(id int, a engine.Action) error
+}
+
+// Server answers HTTP requests against a Game.
+type Server struct {
+	srv  *http.Server
+	ln   net.Listener
+	jobs chan func(Game)
+}
+
+// ActionRequest is the body of POST /action.
+type ActionRequest struct {
+	Player *int `json:"player,omitempty"`
+	lan.WireAction
+}
+
+// Listen serves on addr, which must be a loopback address such as
+// "127.0.0.1:7778" or "localhost"; a missing host means 127.0.0.1 and a
+// missing port DefaultPort.
+func Listen(addr string) (*Server, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host, port = addr, DefaultPort
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if !loopbackHost(host) {
+		return nil, fmt.Errorf("api: %s is not a loopback address", host)
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{ln: ln, jobs: make(chan func(Game))}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /state", s.handleState)
+	mux.HandleFunc("POST /action", s.handleAction)
+	s.srv = &http.Server{Handler: localOnly(mux)}
+	go s.srv.Serve(ln)
+	return s, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Poll runs the requests waiting on g. Call it once a frame from the game
+// loop.
+func (s *Server) Poll(g Game) {
+	for {
+		select {
+		case job := <-s.jobs:
+			job(g)
+		default:
+			return
+		}
+	}
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// run hands job to the game loop and waits until it has run.
+func (s *Server) run(ctx context.Context, job func(Game)) error {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+	done := make(chan struct{})
+	select {
+	case s.jobs <- func(g Game) { job(g); close(done) }:
+	case <-ctx.Done():
+		return errors.New("the game is not responding")
+	}
+	<-done
+	return nil
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	var st State
+	if err := s.run(r.Context(), func(g Game) { st = Snapshot(g) }); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request) {
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("send the action as application/json"))
+		return
+	}
+	var req ActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	a, err := lan.DecodeAction(&req.WireAction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var st State
+	var failed error
+	err = s.run(r.Context(), func(g Game) {
+		gs := g.Engine()
+		if gs == nil {
+			failed = errors.New("no game in progress")
+			return
+		}
+		id := gs.Actor()
+		if req.Player != nil {
+			id = *req.Player
+		}
+		failed = g.Act(id, a)
+		st = Snapshot(g)
+	})
+	switch {
+	case err != nil:
+		writeError(w, http.StatusServiceUnavailable, err)
+	case failed != nil:
+		writeError(w, http.StatusConflict, failed)
+	default:
+		writeJSON(w, http.StatusOK, st)
+	}
+}
+
+// localOnly refuses requests that could have come from a web page rather
+// than a script: any with an Origin header, and any addressed to a host
+// that is not loopback.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, errors.New("cross-origin requests are not allowed"))
+			return
+		}
+		if !loopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not a loopback address", r.Host))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loopbackHost reports whether host, with or without a port, names the
+// loopback interface.
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
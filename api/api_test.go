@@ -0,0 +1,224 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// testGame is a Game with no screen, as in a headless run.
+type testGame struct {
+	s *engine.GameState
+}
+
+func (g *testGame) Engine() *engine.GameState { return g.s }
+func (g *testGame) DialogName() string        { return "" }
+
+func (g *testGame) Act(id int, a engine.Action) error {
+	return g.s.Apply(id, a)
+}
+
+// serve starts a server on a free loopback port, with a game loop polling
+// it until the test ends.
+func serve(t *testing.T, g Game) *Server {
+	t.Helper()
+	srv, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+				srv.Poll(g)
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		srv.Close()
+	})
+	return srv
+}
+
+func newTestGame() *testGame {
+	players := []*player.Player{player.NewPlayer(0, "A", false), player.NewPlayer(1, "B", false)}
+	return &testGame{s: engine.New(players, rng.New(1), engine.StandardRules())}
+}
+
+func TestListen(t *testing.T) {
+	for _, tc := range []struct {
+		addr string
+		ok   bool
+	}{
+		{"127.0.0.1:0", true},
+		{"localhost:0", true},
+		{":0", true},
+		{"0.0.0.0:0", false},
+		{"192.168.1.5:0", false},
+		{"example.com:0", false},
+	} {
+		srv, err := Listen(tc.addr)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: error %v, want ok %t", tc.addr, err, tc.ok)
+		}
+		if err == nil {
+			srv.Close()
+		}
+	}
+}
+
+func TestLoopbackHost(t *testing.T) {
+	for _, tc := range []struct {
+		host string
+		want bool
+	}{
+		{"localhost", true},
+		{"localhost:7778", true},
+		{"127.0.0.1", true},
+		{"127.0.0.9:7778", true},
+		{"[::1]:7778", true},
+		{"::1", true},
+		{"evil.example", false},
+		{"evil.example:7778", false},
+		{"10.0.0.1", false},
+		{"", false},
+	} {
+		if got := loopbackHost(tc.host); got != tc.want {
+			t.Errorf("%q: loopback %t, want %t", tc.host, got, tc.want)
+		}
+	}
+}
+
+func TestRequests(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		host        string // the Host header, if not the server's own
+		origin      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{name: "state", method: "GET", path: "/state", status: http.StatusOK},
+		{name: "state from a page", method: "GET", path: "/state", origin: "http://evil.example",
+			status: http.StatusForbidden},
+		{name: "state for another host", method: "GET", path: "/state", host: "evil.example:7778",
+			status: http.StatusForbidden},
+		{name: "a roll", method: "POST", path: "/action", contentType: "application/json",
+			body: `{"type":"RollDice"}`, status: http.StatusOK},
+		{name: "a roll with a charset", method: "POST", path: "/action", contentType: "application/json; charset=utf-8",
+			body: `{"type":"RollDice"}`, status: http.StatusOK},
+		{name: "a roll as a form", method: "POST", path: "/action", contentType: "text/plain",
+			body: `{"type":"RollDice"}`, status: http.StatusUnsupportedMediaType},
+		{name: "a roll without a type", method: "POST", path: "/action",
+			body: `{"type":"RollDice"}`, status: http.StatusUnsupportedMediaType},
+		{name: "a roll from a page", method: "POST", path: "/action", contentType: "application/json",
+			origin: "http://evil.example", body: `{"type":"RollDice"}`, status: http.StatusForbidden},
+		{name: "a roll for another host", method: "POST", path: "/action", contentType: "application/json",
+			host: "evil.example", body: `{"type":"RollDice"}`, status: http.StatusForbidden},
+		{name: "bad JSON", method: "POST", path: "/action", contentType: "application/json",
+			body: `{"type":`, status: http.StatusBadRequest},
+		{name: "an unknown action", method: "POST", path: "/action", contentType: "application/json",
+			body: `{"type":"Cheat"}`, status: http.StatusBadRequest},
+		{name: "out of turn", method: "POST", path: "/action", contentType: "application/json",
+			body: `{"player":1,"type":"RollDice"}`, status: http.StatusConflict},
+		{name: "an illegal action", method: "POST", path: "/action", contentType: "application/json",
+			body: `{"type":"Bid","data":{"Amount":60}}`, status: http.StatusConflict},
+		{name: "action by GET", method: "GET", path: "/action", status: http.StatusMethodNotAllowed},
+	} {
+		g := newTestGame()
+		srv := serve(t, g)
+		req, err := http.NewRequest(tc.method, "http://"+srv.Addr()+tc.path, strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.host != "" {
+			req.Host = tc.host
+		}
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s: status %d, want %d", tc.name, resp.StatusCode, tc.status)
+		}
+	}
+}
+
+func TestAction(t *testing.T) {
+	g := newTestGame()
+	srv := serve(t, g)
+	resp, err := http.Post("http://"+srv.Addr()+"/action", "application/json",
+		strings.NewReader(`{"type":"RollDice"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var st State
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Phase == engine.PhasePreRoll.String() {
+		t.Errorf("still waiting on a roll after the roll")
+	}
+	if want := g.s.Players[0].Position; st.Players[0].Position != want {
+		t.Errorf("A at %d, want %d", st.Players[0].Position, want)
+	}
+}
+
+func TestState(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		playing bool
+	}{
+		{"in the menus", false},
+		{"in a game", true},
+	} {
+		g := newTestGame()
+		if !tc.playing {
+			g.s = nil
+		} else {
+			g.s.Board.Properties[39].OwnerID = 1
+			g.s.Players[1].AddProperty(39)
+		}
+		st := Snapshot(g)
+		if st.Playing != tc.playing {
+			t.Errorf("%s: playing %t", tc.name, st.Playing)
+		}
+		if !tc.playing {
+			continue
+		}
+		if st.Phase != engine.PhasePreRoll.String() {
+			t.Errorf("%s: phase %q", tc.name, st.Phase)
+		}
+		if len(st.Players) != 2 || len(st.Properties) != 1 || st.Properties[0].Space != 39 ||
+			st.Properties[0].Owner != 1 || st.Properties[0].Group == "" {
+			t.Errorf("%s: players %+v, properties %+v", tc.name, st.Players, st.Properties)
+		}
+		legal := map[string]bool{}
+		for _, w := range st.Legal {
+			legal[w.Type] = true
+		}
+		if !legal["RollDice"] {
+			t.Errorf("%s: legal %v without RollDice", tc.name, legal)
+		}
+	}
+}
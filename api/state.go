@@ -0,0 +1,105 @@
+package api
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/lan"
+)
+
+// State is the JSON served by GET /state.
+type State struct {
+	Playing    bool              `json:"playing"` // false in the menus; nothing else is set
+	Over       bool              `json:"over"`
+	Phase      string            `json:"phase"`
+	Round      int               `json:"round"`
+	Current    int               `json:"current"` // whose turn it is
+	Actor      int               `json:"actor"`   // who the engine is waiting on
+	Dialog     string            `json:"dialog,omitempty"`
+	Players    []PlayerState     `json:"players"`
+	Properties []PropertyState   `json:"properties"` // owned lots only
+	Legal      []*lan.WireAction `json:"legal"`      // what the actor may do now
+}
+
+// PlayerState is one player in State.
+type PlayerState struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	AI         bool   `json:"ai"`
+	Money      int    `json:"money"`
+	NetWorth   int    `json:"net_worth"`
+	Position   int    `json:"position"`
+	InJail     bool   `json:"in_jail"`
+	JailTurns  int    `json:"jail_turns,omitempty"`
+	JailCards  int    `json:"jail_cards,omitempty"`
+	Bankrupt   bool   `json:"bankrupt"`
+	Properties []int  `json:"properties"`
+}
+
+// PropertyState is one owned lot of Board.Properties in State.
+type PropertyState struct {
+	Space     int    `json:"space"`
+	Name      string `json:"name"`
+	Group     string `json:"group,omitempty"`
+	Owner     int    `json:"owner"`
+	Houses    int    `json:"houses"` // 5 is a hotel
+	Mortgaged bool   `json:"mortgaged"`
+}
+
+// Snapshot describes g as it is now.
+func Snapshot(g Game) State {
+	s := g.Engine()
+	if s == nil {
+		return State{}
+	}
+	st := State{
+		Playing: true,
+		Over:    s.IsOver(),
+		Phase:   s.Phase.String(),
+		Round:   s.Round,
+		Current: s.Current,
+		Actor:   s.Actor(),
+		Dialog:  g.DialogName(),
+
+		Properties: []PropertyState{},
+		Legal:      []*lan.WireAction{},
+	}
+	for _, p := range s.Players {
+		st.Players = append(st.Players, PlayerState{
+			ID:         p.ID,
+			Name:       p.Name,
+			AI:         p.IsAI,
+			Money:      p.Money,
+			NetWorth:   s.PlayerNetWorth(p.ID),
+			Position:   p.Position,
+			InJail:     p.InJail,
+			JailTurns:  p.JailTurns,
+			JailCards:  p.GetOutOfJailCards,
+			Bankrupt:   p.Bankrupt,
+			Properties: append([]int{}, p.Properties...),
+		})
+	}
+	for i, prop := range s.Board.Properties {
+		if prop.OwnerID < 0 {
+			continue
+		}
+		space := s.Board.Spaces[i]
+		ps := PropertyState{
+			Space:     i,
+			Name:      space.Name,
+			Owner:     prop.OwnerID,
+			Houses:    prop.Houses,
+			Mortgaged: prop.Mortgaged,
+		}
+		if space.Type == board.SpaceProperty {
+			ps.Group = space.Group.String()
+		}
+		st.Properties = append(st.Properties, ps)
+	}
+	if !st.Over {
+		for _, a := range s.LegalActions(st.Actor) {
+			if w, err := lan.EncodeAction(a); err == nil {
+				st.Legal = append(st.Legal, w)
+			}
+		}
+	}
+	return st
+}
@@ -1,6 +1,9 @@
 package board
 
-import "github.com/AchrafSoltani/MoroccanMonopoly/config"
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
 
 // Board holds all spaces, property states, card decks, and house/hotel pools.
 type Board struct {
@@ -12,13 +15,14 @@ type Board struct {
 	CommunityDeck  *Deck
 }
 
-// NewBoard creates and initialises the full 40-space Moroccan Monopoly board.
-func NewBoard() *Board {
+// NewBoard creates and initialises the full 40-space Moroccan Monopoly board,
+// shuffling the card decks with r.
+func NewBoard(r *rng.Rand) *Board {
 	b := &Board{
 		HousePool:     config.MaxHouses,
 		HotelPool:     config.MaxHotels,
-		ChanceDeck:    NewDeck(ChanceCards()),
-		CommunityDeck: NewDeck(CommunityChestCards()),
+		ChanceDeck:    NewDeck(ChanceCards(), r),
+		CommunityDeck: NewDeck(CommunityChestCards(), r),
 	}
 
 	for i := 0; i < config.SpaceCount; i++ {
@@ -98,6 +102,15 @@ func NewBoard() *Board {
 	return b
 }
 
+// Clone returns an independent copy of the board whose decks reshuffle
+// with r.
+func (b *Board) Clone(r *rng.Rand) *Board {
+	c := *b
+	c.ChanceDeck = b.ChanceDeck.Clone(r)
+	c.CommunityDeck = b.CommunityDeck.Clone(r)
+	return &c
+}
+
 // IsProperty returns true if the space can be owned (property, railroad, or utility).
 func (b *Board) IsProperty(index int) bool {
 	t := b.Spaces[index].Type
@@ -1,6 +1,10 @@
 package board
 
-import "math/rand"
+import (
+	"slices"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
 
 // CardEffect types
 type CardEffectType int
@@ -30,12 +34,15 @@ type Card struct {
 type Deck struct {
 	Cards   []Card
 	Current int
+	rand    *rng.Rand
 }
 
-// NewDeck creates a deck from the given cards and shuffles it.
-func NewDeck(cards []Card) *Deck {
+// NewDeck creates a deck from the given cards and shuffles it with r, which
+// is also used to reshuffle once the deck runs out.
+func NewDeck(cards []Card, r *rng.Rand) *Deck {
 	d := &Deck{
 		Cards: make([]Card, len(cards)),
+		rand:  r,
 	}
 	copy(d.Cards, cards)
 	d.Shuffle()
@@ -44,12 +51,18 @@ func NewDeck(cards []Card) *Deck {
 
 // Shuffle randomises the deck order.
 func (d *Deck) Shuffle() {
-	rand.Shuffle(len(d.Cards), func(i, j int) {
+	d.rand.Shuffle(len(d.Cards), func(i, j int) {
 		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
 	})
 	d.Current = 0
 }
 
+// Clone returns a copy of the deck in its current order that reshuffles
+// with r once it runs out.
+func (d *Deck) Clone(r *rng.Rand) *Deck {
+	return &Deck{Cards: slices.Clone(d.Cards), Current: d.Current, rand: r}
+}
+
 // Draw returns the next card from the deck.
 func (d *Deck) Draw() Card {
 	card := d.Cards[d.Current]
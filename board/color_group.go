@@ -26,3 +26,27 @@ func GroupSize(g ColorGroup) int {
 		return 0
 	}
 }
+
+// String returns the group's display name.
+func (g ColorGroup) String() string {
+	switch g {
+	case GroupBrown:
+		return "Brown"
+	case GroupLightBlue:
+		return "Light Blue"
+	case GroupPink:
+		return "Pink"
+	case GroupOrange:
+		return "Orange"
+	case GroupRed:
+		return "Red"
+	case GroupYellow:
+		return "Yellow"
+	case GroupGreen:
+		return "Green"
+	case GroupDarkBlue:
+		return "Dark Blue"
+	default:
+		return "None"
+	}
+}
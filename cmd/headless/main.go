@@ -0,0 +1,125 @@
+// Command headless runs one game with no window, to be watched and driven
+// through the local control API (see package api). Seats listed in -humans
+// wait for POST /action; the AI plays the others. The server keeps
+// answering after the game ends, until the command is interrupted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/ai"
+	"github.com/AchrafSoltani/MoroccanMonopoly/api"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// idle is how long the loop sleeps while waiting on the API.
+const idle = 10 * time.Millisecond
+
+// headless is a game with no screen, for the API.
+type headless struct {
+	s *engine.GameState
+}
+
+func (h headless) Engine() *engine.GameState { return h.s }
+func (h headless) DialogName() string        { return "" }
+
+func (h headless) Act(id int, a engine.Action) error {
+	if err := h.s.Validate(id, a); err != nil {
+		return err
+	}
+	if h.s.Players[id].IsAI {
+		return fmt.Errorf("%s is played by the AI", h.s.Players[id].Name)
+	}
+	return h.s.Apply(id, a)
+}
+
+func main() {
+	addr := flag.String("api", "127.0.0.1:"+api.DefaultPort, "loopback address to serve the control API on")
+	seats := flag.Int("players", 4, "players in the game (2-4)")
+	humanList := flag.String("humans", "0", "seats driven through the API, comma-separated from 0; the AI plays the rest")
+	level := flag.String("level", "normal", "AI difficulty (easy, normal, hard)")
+	seed := flag.Int64("seed", 0, "random seed for dice and cards (0 = new seed)")
+	delay := flag.Duration("delay", 0, "pause before each AI move, so watchers can follow")
+	flag.Parse()
+
+	if *seats < 2 || *seats > config.MaxPlayers {
+		log.Fatalf("-players must be 2-%d", config.MaxPlayers)
+	}
+	humans, err := parseSeats(*humanList, *seats)
+	if err != nil {
+		log.Fatal(err)
+	}
+	difficulty, ok := parseLevel(*level)
+	if !ok {
+		log.Fatalf("unknown difficulty %q", *level)
+	}
+
+	var players []*player.Player
+	for i := 0; i < *seats; i++ {
+		var p *player.Player
+		if humans[i] {
+			p = player.NewPlayer(i, fmt.Sprintf("Player %d", i+1), false)
+		} else {
+			p = player.NewPlayer(i, fmt.Sprintf("AI %d", i+1), true)
+			p.Difficulty = difficulty
+		}
+		players = append(players, p)
+	}
+	if *seed == 0 {
+		*seed = rng.NewSeed()
+	}
+	h := headless{s: engine.New(players, rng.New(*seed), engine.StandardRules())}
+
+	srv, err := api.Listen(*addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("seed %d; control API on http://%s", *seed, srv.Addr())
+
+	for {
+		srv.Poll(h)
+		if h.s.IsOver() || !h.s.Players[h.s.Actor()].IsAI {
+			time.Sleep(idle)
+			continue
+		}
+		time.Sleep(*delay)
+		for _, a := range ai.Decide(h.s) {
+			if err := h.s.Apply(h.s.Actor(), a); err != nil {
+				break
+			}
+		}
+	}
+}
+
+// parseSeats reads the -humans flag into the seats it names.
+func parseSeats(list string, seats int) ([]bool, error) {
+	humans := make([]bool, seats)
+	for _, f := range strings.Split(list, ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		i, err := strconv.Atoi(f)
+		if err != nil || i < 0 || i >= seats {
+			return nil, fmt.Errorf("-humans: no seat %q", f)
+		}
+		humans[i] = true
+	}
+	return humans, nil
+}
+
+func parseLevel(name string) (player.Difficulty, bool) {
+	for _, d := range player.Difficulties {
+		if strings.EqualFold(name, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,266 @@
+// Command simulate plays all-AI games headlessly and reports how they went:
+// win rate by seat, game length, and how often the first player to complete
+// each colour group before anyone goes bankrupt goes on to win. Game i is
+// seeded with the -seed value plus i, so any run can be repeated exactly.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/ai"
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+const groupCount = int(board.GroupDarkBlue) + 1
+
+// result is the outcome of one simulated game.
+type result struct {
+	Seed   int64
+	Winner int // -1 if the turn limit was reached first
+	Turns  int
+	Rounds int
+	Err    error
+
+	// Player who first owned each whole colour group while every player was
+	// still in, -1 if nobody did. Later monopolies mostly come from taking
+	// over a bankrupt player's properties, which says nothing about the group.
+	FirstMonopoly [groupCount]int
+}
+
+func main() {
+	games := flag.Int("games", 1000, "number of games to play")
+	seats := flag.Int("players", 4, "AI players per game (2-4)")
+	seed := flag.Int64("seed", 1, "seed of the first game; game i uses seed+i")
+	maxTurns := flag.Int("max-turns", 2000, "give up on a game after this many turns")
+	workers := flag.Int("workers", runtime.NumCPU(), "games played in parallel")
+	levelList := flag.String("levels", "normal", "AI difficulty per seat, comma-separated (easy, normal, hard); one value applies to every seat")
+	flag.Parse()
+
+	if *seats < 2 || *seats > config.MaxPlayers {
+		log.Fatalf("-players must be 2-%d", config.MaxPlayers)
+	}
+	levels, err := parseLevels(*levelList, *seats)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *games < 1 || *workers < 1 {
+		log.Fatal("-games and -workers must be at least 1")
+	}
+
+	start := time.Now()
+	seeds := make(chan int64)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range seeds {
+				results <- play(s, levels, *maxTurns)
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < *games; i++ {
+			seeds <- *seed + int64(i)
+		}
+		close(seeds)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var all []result
+	for r := range results {
+		all = append(all, r)
+	}
+
+	fmt.Printf("Simulated %d games of %d AI players (%s), seeds %d-%d, in %s\n\n",
+		*games, *seats, joinLevels(levels), *seed, *seed+int64(*games)-1, time.Since(start).Round(time.Millisecond))
+	report(os.Stdout, all, levels, *maxTurns)
+}
+
+// parseLevels reads the -levels flag into one difficulty per seat.
+func parseLevels(list string, seats int) ([]player.Difficulty, error) {
+	names := strings.Split(list, ",")
+	if len(names) == 1 {
+		for len(names) < seats {
+			names = append(names, names[0])
+		}
+	}
+	if len(names) != seats {
+		return nil, fmt.Errorf("-levels has %d entries for %d players", len(names), seats)
+	}
+
+	var levels []player.Difficulty
+	for _, name := range names {
+		found := false
+		for _, d := range player.Difficulties {
+			if strings.EqualFold(strings.TrimSpace(name), d.String()) {
+				levels = append(levels, d)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown difficulty %q", name)
+		}
+	}
+	return levels, nil
+}
+
+func joinLevels(levels []player.Difficulty) string {
+	var names []string
+	for _, d := range levels {
+		names = append(names, d.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// play runs one game to completion, or until the turn limit.
+func play(seed int64, levels []player.Difficulty, maxTurns int) result {
+	seats := len(levels)
+	var players []*player.Player
+	for i, d := range levels {
+		p := player.NewPlayer(i, fmt.Sprintf("AI %d", i+1), true)
+		p.Difficulty = d
+		players = append(players, p)
+	}
+	s := engine.New(players, rng.New(seed), engine.StandardRules())
+
+	res := result{Seed: seed, Winner: -1, Turns: 1}
+	for i := range res.FirstMonopoly {
+		res.FirstMonopoly[i] = -1
+	}
+	s.Subscribe(func(e engine.Event) {
+		if _, ok := e.(engine.TurnStarted); ok {
+			res.Turns++
+		}
+	})
+
+	for !s.IsOver() && res.Turns <= maxTurns {
+		actions := ai.Decide(s)
+		if len(actions) == 0 {
+			res.Err = fmt.Errorf("no decision in phase %s", s.Phase)
+			break
+		}
+		for _, a := range actions {
+			if err := s.Apply(s.Actor(), a); err != nil {
+				res.Err = err
+				break
+			}
+		}
+		if res.Err != nil {
+			break
+		}
+		if len(s.AlivePlayers()) == seats {
+			recordMonopolies(s, &res)
+		}
+	}
+
+	res.Rounds = s.Round
+	if s.IsOver() {
+		res.Winner = s.AlivePlayers()[0].ID
+	}
+	return res
+}
+
+// recordMonopolies notes who completed any group nobody had completed yet.
+func recordMonopolies(s *engine.GameState, res *result) {
+	for g := board.GroupBrown; g <= board.GroupDarkBlue; g++ {
+		if res.FirstMonopoly[g] != -1 {
+			continue
+		}
+		if owner := s.Board.Properties[s.Board.SpacesInGroup(g)[0]].OwnerID; owner >= 0 && s.HasMonopoly(owner, g) {
+			res.FirstMonopoly[g] = owner
+		}
+	}
+}
+
+func report(out io.Writer, results []result, levels []player.Difficulty, maxTurns int) {
+	seats := len(levels)
+	finished, failed := 0, 0
+	var wins [config.MaxPlayers]int
+	turns, rounds := 0, 0
+	minTurns, maxGame := 0, 0
+	var completed, completerWon [groupCount]int
+
+	for _, r := range results {
+		if r.Err != nil {
+			if failed == 0 {
+				log.Printf("seed %d: %v", r.Seed, r.Err)
+			}
+			failed++
+			continue
+		}
+		if r.Winner < 0 {
+			continue
+		}
+		finished++
+		wins[r.Winner]++
+		turns += r.Turns
+		rounds += r.Rounds
+		if minTurns == 0 || r.Turns < minTurns {
+			minTurns = r.Turns
+		}
+		if r.Turns > maxGame {
+			maxGame = r.Turns
+		}
+		for g, owner := range r.FirstMonopoly {
+			if owner < 0 {
+				continue
+			}
+			completed[g]++
+			if owner == r.Winner {
+				completerWon[g]++
+			}
+		}
+	}
+
+	fmt.Fprintf(out, "Finished: %d   Unfinished after %d turns: %d   Failed: %d\n\n",
+		finished, maxTurns, len(results)-finished-failed, failed)
+	if finished == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Seat\tLevel\tWins\tWin rate\t")
+	for i, d := range levels {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%.1f%%\t\n", i+1, d, wins[i], percent(wins[i], finished))
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nGame length: %.1f turns (%d-%d), %.1f rounds\n\n",
+		float64(turns)/float64(finished), minTurns, maxGame, float64(rounds)/float64(finished))
+
+	// Lift compares the completer's win rate to an even share of the wins
+	fmt.Fprintln(w, "Group\tCompleted\tCompleter won\tLift\t")
+	for g := board.GroupBrown; g <= board.GroupDarkBlue; g++ {
+		if completed[g] == 0 {
+			fmt.Fprintf(w, "%s\t0.0%%\t-\t-\t\n", g)
+			continue
+		}
+		winRate := percent(completerWon[g], completed[g])
+		fmt.Fprintf(w, "%s\t%.1f%%\t%.1f%%\t%.2fx\t\n",
+			g, percent(completed[g], finished), winRate, winRate/100*float64(seats))
+	}
+	w.Flush()
+}
+
+func percent(n, of int) float64 {
+	return 100 * float64(n) / float64(of)
+}
@@ -31,6 +31,7 @@ const (
 	StartingMoney  = 1500
 	GoSalary       = 200
 	MaxPlayers     = 4
+	TokenColors    = 8 // colours a player can pick for their token
 	MinPlayers     = 2
 	MaxHouses      = 32
 	MaxHotels      = 12
@@ -43,8 +44,9 @@ const (
 	MaxJailTurns   = 3
 	IncomeTax      = 200
 	LuxuryTax      = 100
-	MortgageRate   = 50  // percent of price
-	UnmortgageRate = 110 // percent of mortgage value
+	MortgageRate   = 50 // percent of price
+	InterestRate   = 10 // percent of mortgage value charged to lift or take over a mortgage
+	BidIncrement   = 10 // minimum auction raise
 )
 
 // Layout holds geometry values computed from window dimensions.
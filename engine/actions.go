@@ -0,0 +1,446 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+)
+
+// ErrIllegalAction is returned by Validate and Apply when an action is not
+// allowed; the wrapping error says why.
+var ErrIllegalAction = errors.New("illegal action")
+
+// Action is a player decision that can be applied to a GameState.
+type Action interface {
+	isAction()
+}
+
+// RollDice rolls the dice; from jail it is an attempt to roll doubles.
+type RollDice struct{}
+
+// BuyProperty buys the unowned property the current player landed on.
+type BuyProperty struct{}
+
+// DeclineBuy declines the property, sending it to auction.
+type DeclineBuy struct{}
+
+// PayJailFine pays the fine to leave jail before rolling.
+type PayJailFine struct{}
+
+// UseJailCard spends a Get Out of Jail Free card.
+type UseJailCard struct{}
+
+// PayIncomeTax pays income tax, either flat or as 10% of net worth.
+type PayIncomeTax struct {
+	Percent bool
+}
+
+// Build adds a house (or hotel) to a property.
+type Build struct {
+	Space int
+}
+
+// SellHouse sells a house (or breaks a hotel back to houses) for half cost.
+type SellHouse struct {
+	Space int
+}
+
+// Mortgage mortgages an unimproved property.
+type Mortgage struct {
+	Space int
+}
+
+// Unmortgage lifts the mortgage on a property.
+type Unmortgage struct {
+	Space int
+}
+
+// Bid raises the current auction to Amount.
+type Bid struct {
+	Amount int
+}
+
+// Pass drops out of the current auction.
+type Pass struct{}
+
+// ProposeTrade offers a trade to another player.
+type ProposeTrade struct {
+	Offer TradeOffer
+}
+
+// AcceptTrade accepts the pending trade offer.
+type AcceptTrade struct{}
+
+// DeclineTrade declines the pending trade offer.
+type DeclineTrade struct{}
+
+// EndTurn finishes the current turn.
+type EndTurn struct{}
+
+// PayDebt pays the debt being settled once the debtor has raised the cash.
+type PayDebt struct{}
+
+// DeclareBankruptcy concedes a debt the debtor cannot or will not pay.
+type DeclareBankruptcy struct{}
+
+// ReceiveMortgaged decides on a mortgaged property received by trade or
+// bankruptcy: Unmortgage pays it off now, otherwise the interest is paid and
+// it stays mortgaged.
+type ReceiveMortgaged struct{ Unmortgage bool }
+
+func (RollDice) isAction()          {}
+func (BuyProperty) isAction()       {}
+func (DeclineBuy) isAction()        {}
+func (PayJailFine) isAction()       {}
+func (UseJailCard) isAction()       {}
+func (PayIncomeTax) isAction()      {}
+func (Build) isAction()             {}
+func (SellHouse) isAction()         {}
+func (Mortgage) isAction()          {}
+func (Unmortgage) isAction()        {}
+func (Bid) isAction()               {}
+func (Pass) isAction()              {}
+func (ProposeTrade) isAction()      {}
+func (AcceptTrade) isAction()       {}
+func (DeclineTrade) isAction()      {}
+func (EndTurn) isAction()           {}
+func (PayDebt) isAction()           {}
+func (DeclareBankruptcy) isAction() {}
+func (ReceiveMortgaged) isAction()  {}
+
+// LegalActions returns every action playerID may take right now, or nil if
+// the game is not waiting on them. Bid and ProposeTrade take parameters:
+// the list holds the minimum raise and one empty offer per possible
+// partner, and Validate checks the values actually chosen.
+func (s *GameState) LegalActions(playerID int) []Action {
+	if s.IsOver() || playerID != s.Actor() {
+		return nil
+	}
+	p := s.Players[playerID]
+
+	candidates := []Action{
+		RollDice{}, BuyProperty{}, DeclineBuy{},
+		PayJailFine{}, UseJailCard{},
+		PayIncomeTax{}, PayIncomeTax{Percent: true},
+	}
+	for _, idx := range p.Properties {
+		candidates = append(candidates, Build{Space: idx}, SellHouse{Space: idx},
+			Mortgage{Space: idx}, Unmortgage{Space: idx})
+	}
+	candidates = append(candidates, Bid{Amount: s.AuctionHighBid + config.BidIncrement}, Pass{},
+		AcceptTrade{}, DeclineTrade{}, EndTurn{}, PayDebt{}, DeclareBankruptcy{},
+		ReceiveMortgaged{Unmortgage: true}, ReceiveMortgaged{})
+
+	var legal []Action
+	for _, a := range candidates {
+		if s.Validate(playerID, a) == nil {
+			legal = append(legal, a)
+		}
+	}
+
+	if s.canManage() {
+		for _, other := range s.Players {
+			if other.ID != playerID && !other.Bankrupt {
+				legal = append(legal, ProposeTrade{Offer: TradeOffer{FromPlayer: playerID, ToPlayer: other.ID}})
+			}
+		}
+	}
+	return legal
+}
+
+// IsLegal reports whether playerID may take the action right now.
+func (s *GameState) IsLegal(playerID int, a Action) bool {
+	return s.Validate(playerID, a) == nil
+}
+
+// Validate checks whether playerID may take the action right now. The
+// returned error wraps ErrIllegalAction and explains the rejection.
+func (s *GameState) Validate(playerID int, a Action) error {
+	if s.IsOver() {
+		return illegal(a, "the game is over")
+	}
+	if playerID != s.Actor() {
+		return illegal(a, "waiting on another player")
+	}
+	p := s.Players[playerID]
+
+	phase := func(want ...TurnPhase) error {
+		for _, w := range want {
+			if s.Phase == w {
+				return nil
+			}
+		}
+		return illegal(a, "not allowed during "+s.Phase.String())
+	}
+
+	switch a := a.(type) {
+	case RollDice:
+		return phase(PhasePreRoll, PhaseJailDecision)
+
+	case BuyProperty:
+		if err := phase(PhaseBuyDecision); err != nil {
+			return err
+		}
+		if p.Money < s.Board.Spaces[p.Position].Price {
+			return illegal(a, "not enough money")
+		}
+
+	case DeclineBuy:
+		return phase(PhaseBuyDecision)
+
+	case PayJailFine:
+		if err := phase(PhaseJailDecision); err != nil {
+			return err
+		}
+		if p.Money < s.Rules.JailFine {
+			return illegal(a, "not enough money")
+		}
+
+	case UseJailCard:
+		if err := phase(PhaseJailDecision); err != nil {
+			return err
+		}
+		if p.GetOutOfJailCards <= 0 {
+			return illegal(a, "no Get Out of Jail Free card")
+		}
+
+	case PayIncomeTax:
+		return phase(PhaseIncomeTax)
+
+	case Build:
+		if err := s.validateOwnedSpace(a, p.ID, a.Space); err != nil {
+			return err
+		}
+		if s.Phase == PhaseDebt {
+			return illegal(a, "pay the debt first")
+		}
+		if !s.CanBuildOnSpace(a.Space) {
+			return illegal(a, "cannot build on "+s.Board.Spaces[a.Space].Name)
+		}
+		if p.Money < s.Board.Spaces[a.Space].HouseCost {
+			return illegal(a, "not enough money")
+		}
+
+	case SellHouse:
+		if err := s.validateOwnedSpace(a, p.ID, a.Space); err != nil {
+			return err
+		}
+		if !s.CanSellHouseOnSpace(a.Space) {
+			if s.Board.Properties[a.Space].Houses == config.HotelLevel && s.Board.HousePool < config.HousesPerHotel {
+				return illegal(a, "the bank has too few houses to break the hotel")
+			}
+			return illegal(a, "no house to sell on "+s.Board.Spaces[a.Space].Name)
+		}
+
+	case Mortgage:
+		if err := s.validateOwnedSpace(a, p.ID, a.Space); err != nil {
+			return err
+		}
+		prop := s.Board.Properties[a.Space]
+		if prop.Mortgaged {
+			return illegal(a, "already mortgaged")
+		}
+		if prop.Houses > 0 {
+			return illegal(a, "sell the houses first")
+		}
+
+	case Unmortgage:
+		if err := s.validateOwnedSpace(a, p.ID, a.Space); err != nil {
+			return err
+		}
+		if s.Phase == PhaseDebt {
+			return illegal(a, "pay the debt first")
+		}
+		if !s.Board.Properties[a.Space].Mortgaged {
+			return illegal(a, "not mortgaged")
+		}
+		if p.Money < s.UnmortgageCost(a.Space) {
+			return illegal(a, "not enough money")
+		}
+
+	case Bid:
+		if err := phase(PhaseAuction); err != nil {
+			return err
+		}
+		if a.Amount < s.AuctionHighBid+config.BidIncrement {
+			return illegal(a, fmt.Sprintf("bid must be at least %d MAD", s.AuctionHighBid+config.BidIncrement))
+		}
+		if p.Money < a.Amount {
+			return illegal(a, "not enough money")
+		}
+
+	case Pass:
+		return phase(PhaseAuction)
+
+	case ProposeTrade:
+		if err := phase(PhasePreRoll, PhasePostAction, PhaseDebt); err != nil {
+			return err
+		}
+		if a.Offer.FromPlayer != p.ID {
+			return illegal(a, "offer must come from the proposer")
+		}
+		return s.validateOffer(a, a.Offer)
+
+	case AcceptTrade:
+		if err := phase(PhaseTradeResponse); err != nil {
+			return err
+		}
+		if s.Players[s.PendingOffer.ToPlayer].Money < s.PendingOffer.WantedMoney {
+			return illegal(a, "not enough money")
+		}
+
+	case DeclineTrade:
+		return phase(PhaseTradeResponse)
+
+	case EndTurn:
+		return phase(PhasePostAction)
+
+	case PayDebt:
+		if err := phase(PhaseDebt); err != nil {
+			return err
+		}
+		if p.Money < s.Debts[0].Amount {
+			return illegal(a, fmt.Sprintf("%d MAD still to raise", s.Debts[0].Amount-p.Money))
+		}
+
+	case DeclareBankruptcy:
+		return phase(PhaseDebt)
+
+	case ReceiveMortgaged:
+		if err := phase(PhaseTransfer); err != nil {
+			return err
+		}
+		if a.Unmortgage && p.Money < s.UnmortgageCost(s.Transfers[0].Space) {
+			return illegal(a, "not enough money")
+		}
+
+	default:
+		return illegal(a, "unknown action")
+	}
+	return nil
+}
+
+// Apply validates an action for playerID and performs it. Illegal actions
+// are rejected with the error from Validate and leave the state untouched.
+func (s *GameState) Apply(playerID int, a Action) error {
+	if err := s.Validate(playerID, a); err != nil {
+		return err
+	}
+	p := s.Players[playerID]
+
+	switch a := a.(type) {
+	case RollDice:
+		s.rollDice()
+	case BuyProperty:
+		s.buyProperty()
+	case DeclineBuy:
+		s.declineBuy()
+	case PayJailFine:
+		s.payJailFine(p)
+	case UseJailCard:
+		s.useJailCard(p)
+	case PayIncomeTax:
+		s.payIncomeTax(p, a.Percent)
+	case Build:
+		s.build(p, a.Space)
+	case SellHouse:
+		s.sellHouse(p, a.Space)
+	case Mortgage:
+		s.mortgage(p, a.Space)
+	case Unmortgage:
+		s.unmortgage(p, a.Space)
+	case Bid:
+		s.bid(p, a.Amount)
+	case Pass:
+		s.pass(p)
+	case ProposeTrade:
+		s.proposeTrade(a.Offer)
+	case AcceptTrade:
+		s.acceptTrade()
+	case DeclineTrade:
+		s.declineTrade()
+	case EndTurn:
+		s.endTurn()
+	case PayDebt:
+		s.settleDebt()
+	case DeclareBankruptcy:
+		s.concede()
+	case ReceiveMortgaged:
+		s.receiveMortgaged(a.Unmortgage)
+	}
+
+	// Settle what the action left pending. A debt conceded here can hand
+	// mortgaged lots to the creditor, so transfers are checked again.
+	s.updateTransfers()
+	s.updateDebts()
+	s.updateTransfers()
+	s.auctionQueued()
+	return nil
+}
+
+// validateOwnedSpace checks that a property-management action targets a
+// property the player owns, at a point in the turn where that is allowed.
+func (s *GameState) validateOwnedSpace(a Action, playerID, idx int) error {
+	if !s.canManage() {
+		return illegal(a, "not allowed during "+s.Phase.String())
+	}
+	if idx < 0 || idx >= len(s.Board.Spaces) || s.Board.Properties[idx].OwnerID != playerID {
+		return illegal(a, "property not owned")
+	}
+	return nil
+}
+
+// validateOffer checks that both sides of a trade hold what they would give.
+func (s *GameState) validateOffer(a Action, offer TradeOffer) error {
+	if offer.ToPlayer < 0 || offer.ToPlayer >= len(s.Players) || offer.ToPlayer == offer.FromPlayer ||
+		s.Players[offer.ToPlayer].Bankrupt {
+		return illegal(a, "invalid trade partner")
+	}
+	from := s.Players[offer.FromPlayer]
+	to := s.Players[offer.ToPlayer]
+
+	if len(offer.OfferedProps) == 0 && len(offer.WantedProps) == 0 &&
+		offer.OfferedMoney == 0 && offer.WantedMoney == 0 &&
+		offer.OfferedJailCards == 0 && offer.WantedJailCards == 0 {
+		return illegal(a, "empty offer")
+	}
+	seen := make(map[int]bool)
+	for _, idx := range append(append([]int(nil), offer.OfferedProps...), offer.WantedProps...) {
+		if seen[idx] {
+			return illegal(a, "property listed twice")
+		}
+		seen[idx] = true
+	}
+	for _, idx := range offer.OfferedProps {
+		if !from.OwnsProperty(idx) || !s.CanTradeProperty(idx) {
+			return illegal(a, "cannot offer that property")
+		}
+	}
+	for _, idx := range offer.WantedProps {
+		if !to.OwnsProperty(idx) || !s.CanTradeProperty(idx) {
+			return illegal(a, "cannot request that property")
+		}
+	}
+	if offer.OfferedMoney < 0 || offer.WantedMoney < 0 ||
+		offer.OfferedJailCards < 0 || offer.WantedJailCards < 0 {
+		return illegal(a, "negative amounts")
+	}
+	if offer.OfferedMoney > from.Money || offer.WantedMoney > to.Money {
+		return illegal(a, "not enough money")
+	}
+	if offer.OfferedJailCards > from.GetOutOfJailCards || offer.WantedJailCards > to.GetOutOfJailCards {
+		return illegal(a, "not enough jail cards")
+	}
+	return nil
+}
+
+// canManage reports whether the player being waited on may manage their
+// properties and trade. While settling a debt they may only raise cash.
+func (s *GameState) canManage() bool {
+	return s.Phase == PhasePreRoll || s.Phase == PhasePostAction || s.Phase == PhaseDebt
+}
+
+func illegal(a Action, reason string) error {
+	return fmt.Errorf("%T: %s: %w", a, reason, ErrIllegalAction)
+}
@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+)
+
+func TestBuy(t *testing.T) {
+	s := newGame(2)
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+	expectPhase(t, s, PhaseBuyDecision, 0)
+
+	apply(t, s, BuyProperty{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if s.Board.Properties[3].OwnerID != 0 || s.Players[0].Money != 1440 {
+		t.Errorf("owner %d, money %d after buying", s.Board.Properties[3].OwnerID, s.Players[0].Money)
+	}
+
+	apply(t, s, EndTurn{})
+	expectPhase(t, s, PhasePreRoll, 1)
+}
+
+func TestDeclineBuy(t *testing.T) {
+	s := newGame(3)
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+	apply(t, s, DeclineBuy{})
+	expectPhase(t, s, PhaseAuction, 1)
+
+	apply(t, s, Bid{Amount: 30})
+	expectPhase(t, s, PhaseAuction, 2)
+	apply(t, s, Pass{})
+	expectPhase(t, s, PhaseAuction, 0)
+	apply(t, s, Pass{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if s.Board.Properties[3].OwnerID != 1 || s.Players[1].Money != 1470 {
+		t.Errorf("owner %d, winner's money %d after the auction", s.Board.Properties[3].OwnerID, s.Players[1].Money)
+	}
+}
+
+func TestDoublesRollAgain(t *testing.T) {
+	s := newGame(2)
+	events := record(s)
+	loadDice(t, s, 2, 2)
+	apply(t, s, RollDice{})
+	expectPhase(t, s, PhaseIncomeTax, 0)
+
+	apply(t, s, PayIncomeTax{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if s.Players[0].Money != 1500-config.IncomeTax {
+		t.Errorf("money %d after the flat tax", s.Players[0].Money)
+	}
+
+	apply(t, s, EndTurn{})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if count[RollAgain](*events) != 1 {
+		t.Error("doubles did not earn another roll")
+	}
+}
+
+func TestThreeDoubles(t *testing.T) {
+	s := newGame(2)
+	s.DoublesCount = 2
+	loadDice(t, s, 3, 3)
+	apply(t, s, RollDice{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if p := s.Players[0]; !p.InJail || p.Position != config.JailPosition {
+		t.Fatal("a third double did not send the player to jail")
+	}
+
+	apply(t, s, EndTurn{})
+	expectPhase(t, s, PhasePreRoll, 1)
+}
+
+func TestJail(t *testing.T) {
+	jailed := func() *GameState {
+		s := newGame(2)
+		s.Players[0].InJail = true
+		s.Players[0].Position = config.JailPosition
+		s.StartTurn()
+		return s
+	}
+
+	s := jailed()
+	expectPhase(t, s, PhaseJailDecision, 0)
+	apply(t, s, PayJailFine{})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if p := s.Players[0]; p.InJail || p.Money != 1500-config.JailFine {
+		t.Errorf("in jail %t with %d after paying the fine", p.InJail, p.Money)
+	}
+
+	s = jailed()
+	if s.IsLegal(0, UseJailCard{}) {
+		t.Error("a card can be used without holding one")
+	}
+	s.Players[0].GetOutOfJailCards = 1
+	apply(t, s, UseJailCard{})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if p := s.Players[0]; p.InJail || p.GetOutOfJailCards != 0 {
+		t.Error("the card did not free the player")
+	}
+
+	s = jailed()
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if p := s.Players[0]; !p.InJail || p.JailTurns != 1 || p.Position != config.JailPosition {
+		t.Error("failing to roll doubles let the player out")
+	}
+
+	s = jailed()
+	loadDice(t, s, 4, 4)
+	apply(t, s, RollDice{})
+	if p := s.Players[0]; p.InJail || p.Position != config.JailPosition+8 {
+		t.Error("doubles did not let the player out")
+	}
+}
+
+func TestManageProperties(t *testing.T) {
+	s := newGame(2)
+	own(s, 0, 1, 3)
+
+	apply(t, s, Build{Space: 1})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if s.Board.Properties[1].Houses != 1 || s.Players[0].Money != 1450 {
+		t.Fatal("building did not add a house")
+	}
+	if err := s.Apply(0, Mortgage{Space: 1}); !errors.Is(err, ErrIllegalAction) {
+		t.Error("mortgaged a lot with a house on it")
+	}
+
+	apply(t, s, SellHouse{Space: 1})
+	apply(t, s, Mortgage{Space: 3})
+	if s.Players[0].Money != 1500-25+30 || !s.Board.Properties[3].Mortgaged {
+		t.Fatalf("money %d after selling and mortgaging", s.Players[0].Money)
+	}
+	if s.IsLegal(0, Build{Space: 1}) {
+		t.Error("built in a group with a mortgaged lot")
+	}
+
+	apply(t, s, Unmortgage{Space: 3})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if s.Board.Properties[3].Mortgaged || s.Players[0].Money != 1505-s.UnmortgageCost(3) {
+		t.Error("unmortgaging did not clear the mortgage")
+	}
+
+	loadDice(t, s, 2, 3)
+	apply(t, s, RollDice{})
+	expectPhase(t, s, PhaseBuyDecision, 0)
+	if s.IsLegal(0, Build{Space: 1}) {
+		t.Error("built while deciding on a purchase")
+	}
+}
+
+func TestTrade(t *testing.T) {
+	s := newGame(2)
+	own(s, 0, 1)
+	own(s, 1, 3)
+	offer := TradeOffer{FromPlayer: 0, ToPlayer: 1, OfferedProps: []int{1}, WantedProps: []int{3}, OfferedMoney: 20}
+
+	apply(t, s, ProposeTrade{Offer: offer})
+	expectPhase(t, s, PhaseTradeResponse, 1)
+	apply(t, s, DeclineTrade{})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if s.Board.Properties[1].OwnerID != 0 {
+		t.Fatal("a declined trade went through")
+	}
+
+	apply(t, s, ProposeTrade{Offer: offer})
+	apply(t, s, AcceptTrade{})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if s.Board.Properties[1].OwnerID != 1 || s.Board.Properties[3].OwnerID != 0 ||
+		s.Players[0].Money != 1480 || s.Players[1].Money != 1520 {
+		t.Error("the accepted trade did not swap the lots and cash")
+	}
+}
+
+func TestWaitingOnAnotherPlayer(t *testing.T) {
+	s := newGame(2)
+	if err := s.Apply(1, RollDice{}); !errors.Is(err, ErrIllegalAction) {
+		t.Errorf("player 1 rolled on player 0's turn: %v", err)
+	}
+	if legal := s.LegalActions(1); legal != nil {
+		t.Errorf("player 1 may take %v out of turn", legal)
+	}
+}
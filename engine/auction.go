@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// startAuction begins an auction for the given property.
+func (s *GameState) startAuction(spaceIndex int) {
+	s.AuctionSpaceIdx = spaceIndex
+	s.AuctionHighBid = 0
+	s.AuctionHighBidder = -1
+	s.AuctionCurrent = s.Current
+
+	// Mark all non-bankrupt players as active
+	for i, p := range s.Players {
+		s.AuctionActive[i] = !p.Bankrupt
+	}
+
+	s.Phase = PhaseAuction
+	s.emit(AuctionStarted{Space: spaceIndex})
+	s.advanceAuction()
+}
+
+// advanceAuction moves to the next active bidder.
+func (s *GameState) advanceAuction() {
+	// Find next active player
+	for {
+		s.AuctionCurrent = (s.AuctionCurrent + 1) % len(s.Players)
+		if s.AuctionActive[s.AuctionCurrent] {
+			break
+		}
+		// Check if only one active remains
+		activeCount := 0
+		lastActive := -1
+		for i := 0; i < len(s.Players); i++ {
+			if s.AuctionActive[i] {
+				activeCount++
+				lastActive = i
+			}
+		}
+		if activeCount <= 1 {
+			s.endAuction(lastActive)
+			return
+		}
+	}
+
+	// Check if auction should end (everyone passed except high bidder)
+	activeCount := 0
+	for i := 0; i < len(s.Players); i++ {
+		if s.AuctionActive[i] {
+			activeCount++
+		}
+	}
+	if activeCount <= 1 {
+		s.endAuction(s.AuctionHighBidder)
+	}
+}
+
+// bid raises the auction on behalf of the current bidder.
+func (s *GameState) bid(p *player.Player, amount int) {
+	s.AuctionHighBid = amount
+	s.AuctionHighBidder = s.AuctionCurrent
+	s.emit(BidPlaced{Player: p.ID, Amount: amount})
+	s.advanceAuction()
+}
+
+// pass drops the current bidder out of the auction.
+func (s *GameState) pass(p *player.Player) {
+	s.AuctionActive[s.AuctionCurrent] = false
+	s.emit(AuctionPassed{Player: p.ID})
+	s.advanceAuction()
+}
+
+// endAuction finishes the auction, transferring property to the winner.
+func (s *GameState) endAuction(winnerIdx int) {
+	if winnerIdx < 0 || s.AuctionHighBid <= 0 {
+		s.emit(AuctionUnsold{Space: s.AuctionSpaceIdx})
+	} else {
+		winner := s.Players[winnerIdx]
+		winner.Pay(s.AuctionHighBid)
+		winner.AddProperty(s.AuctionSpaceIdx)
+		s.Board.Properties[s.AuctionSpaceIdx].OwnerID = winnerIdx
+		s.emit(AuctionWon{Player: winnerIdx, Space: s.AuctionSpaceIdx, Price: s.AuctionHighBid})
+	}
+
+	// Reset auction state
+	for i := range s.AuctionActive {
+		s.AuctionActive[i] = false
+	}
+
+	// Back to the turn; auctionQueued starts the next lot, if any
+	s.Phase = s.AuctionReturnPhase
+}
+
+// returnToBank hands a bankrupt player's property back to the bank: its
+// buildings go back to the pool and the lot is queued for auction, unless
+// the house rules do without auctions.
+func (s *GameState) returnToBank(spaceIndex int) {
+	prop := &s.Board.Properties[spaceIndex]
+	if prop.Houses == config.HotelLevel {
+		s.Board.HotelPool++
+	} else {
+		s.Board.HousePool += prop.Houses
+	}
+	prop.OwnerID = -1
+	prop.Mortgaged = false
+	prop.Houses = 0
+	if !s.Rules.NoAuctions {
+		s.AuctionQueue = append(s.AuctionQueue, spaceIndex)
+	}
+}
+
+// auctionQueued starts the next queued auction once no debt, auction,
+// trade or mortgaged transfer is in progress. The lots go one at a time
+// among the survivors, and the turn resumes where it was afterwards.
+func (s *GameState) auctionQueued() {
+	if s.IsOver() {
+		s.AuctionQueue = nil
+		return
+	}
+	switch s.Phase {
+	case PhaseAuction, PhaseDebt, PhaseTradeResponse, PhaseTransfer:
+		return
+	}
+	if len(s.AuctionQueue) == 0 {
+		return
+	}
+	next := s.AuctionQueue[0]
+	s.AuctionQueue = s.AuctionQueue[1:]
+	s.AuctionReturnPhase = s.Phase
+	s.startAuction(next)
+}
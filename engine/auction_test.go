@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+)
+
+func TestQueuedAuctionResumesTurn(t *testing.T) {
+	s := newGame(3)
+	own(s, 0, 1)
+	s.AuctionQueue = []int{6, 8}
+
+	// Any action starts the queue once nothing else is in progress
+	apply(t, s, Mortgage{Space: 1})
+	expectPhase(t, s, PhaseAuction, 1)
+	if s.AuctionSpaceIdx != 6 || s.AuctionReturnPhase != PhasePreRoll {
+		t.Fatalf("auctioning space %d back to %s", s.AuctionSpaceIdx, s.AuctionReturnPhase)
+	}
+	apply(t, s, Bid{Amount: 50})
+	apply(t, s, Pass{})
+	apply(t, s, Pass{})
+	if s.Board.Properties[6].OwnerID != 1 {
+		t.Fatal("the highest bidder did not win the lot")
+	}
+
+	expectPhase(t, s, PhaseAuction, 1)
+	if s.AuctionSpaceIdx != 8 {
+		t.Fatalf("auctioning space %d, want 8", s.AuctionSpaceIdx)
+	}
+	apply(t, s, Pass{})
+	apply(t, s, Pass{})
+	if s.Board.Properties[8].OwnerID != -1 {
+		t.Error("a lot nobody bid on was sold")
+	}
+
+	// The player had not rolled yet, and still has to
+	expectPhase(t, s, PhasePreRoll, 0)
+	if len(s.AuctionQueue) != 0 {
+		t.Errorf("%v still queued", s.AuctionQueue)
+	}
+}
+
+func TestQueuedAuctionWaits(t *testing.T) {
+	s := newGame(3)
+	own(s, 1, 3)
+	s.AuctionQueue = []int{6}
+
+	apply(t, s, ProposeTrade{Offer: TradeOffer{FromPlayer: 0, ToPlayer: 1, WantedProps: []int{3}, OfferedMoney: 100}})
+	expectPhase(t, s, PhaseTradeResponse, 1)
+	apply(t, s, AcceptTrade{})
+	expectPhase(t, s, PhaseAuction, 1)
+	if s.AuctionReturnPhase != PhasePreRoll {
+		t.Errorf("auction returns to %s, want %s", s.AuctionReturnPhase, PhasePreRoll)
+	}
+}
+
+func TestAuctionLastBidderWins(t *testing.T) {
+	s := newGame(2)
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+	apply(t, s, DeclineBuy{})
+	expectPhase(t, s, PhaseAuction, 1)
+
+	apply(t, s, Bid{Amount: 10})
+	expectPhase(t, s, PhaseAuction, 0)
+	if s.IsLegal(0, Bid{Amount: 15}) {
+		t.Error("a raise below the minimum increment was allowed")
+	}
+	if s.IsLegal(0, Bid{Amount: 1510}) {
+		t.Error("a bid above the bidder's cash was allowed")
+	}
+	apply(t, s, Bid{Amount: 20})
+	apply(t, s, Pass{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if s.Board.Properties[3].OwnerID != 0 || s.Players[0].Money != 1480 {
+		t.Error("the last bidder did not win the lot")
+	}
+}
+
+func TestBankruptcyToBank(t *testing.T) {
+	s := newGame(3)
+	own(s, 0, 1, 3, 5)
+	s.Board.Properties[1].Houses = 2
+	s.Board.Properties[3].Houses = 2
+	s.Board.HousePool -= 4
+	s.Board.Properties[5].Mortgaged = true
+	s.Players[0].Money = 10
+	s.Players[0].Position = 35
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+	expectPhase(t, s, PhaseDebt, 0)
+	if d := s.Debts[0]; d.Creditor != Bank || d.Amount != config.LuxuryTax {
+		t.Fatalf("debt %+v, want the luxury tax to the bank", d)
+	}
+
+	apply(t, s, DeclareBankruptcy{})
+	if !s.Players[0].Bankrupt || s.Board.HousePool != config.MaxHouses {
+		t.Fatal("the houses did not go back to the bank")
+	}
+	for _, idx := range []int{1, 3, 5} {
+		if prop := s.Board.Properties[idx]; prop.OwnerID != -1 || prop.Mortgaged {
+			t.Errorf("space %d still owned or mortgaged", idx)
+		}
+	}
+
+	// Each lot is auctioned among the survivors in turn
+	for _, idx := range []int{1, 3, 5} {
+		expectPhase(t, s, PhaseAuction, 1)
+		if s.AuctionSpaceIdx != idx {
+			t.Fatalf("auctioning space %d, want %d", s.AuctionSpaceIdx, idx)
+		}
+		apply(t, s, Pass{})
+	}
+	expectPhase(t, s, PhasePostAction, 0)
+	apply(t, s, EndTurn{})
+	expectPhase(t, s, PhasePreRoll, 1)
+}
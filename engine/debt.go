@@ -0,0 +1,143 @@
+package engine
+
+import "github.com/AchrafSoltani/MoroccanMonopoly/player"
+
+// Debt is money a player owes but could not pay on the spot.
+type Debt struct {
+	Debtor   int
+	Creditor int // player ID, Bank or FreeParking
+	Amount   int
+	Rent     bool // rent for landing on Space
+	Space    int
+}
+
+// payDebt pays a debt straight away if the debtor has the cash, and
+// reports whether it did. Otherwise the debt stands until they raise the
+// money or concede; Apply then moves to the debt phase. creditor is nil
+// when paying the bank.
+func (s *GameState) payDebt(debtor *player.Player, creditor *player.Player, amount int) bool {
+	id := Bank
+	if creditor != nil {
+		id = creditor.ID
+	}
+	return s.owe(Debt{Debtor: debtor.ID, Creditor: id, Amount: amount})
+}
+
+// payRent pays rent for landing on space, as payDebt. RentPaid is reported
+// once the money changes hands, which may be when the debt is settled.
+func (s *GameState) payRent(debtor, owner *player.Player, amount, space int) {
+	if s.owe(Debt{Debtor: debtor.ID, Creditor: owner.ID, Amount: amount, Rent: true, Space: space}) {
+		s.emit(RentPaid{From: debtor.ID, To: owner.ID, Amount: amount, Space: space})
+	}
+}
+
+// payFee charges a tax or fine, which feeds the Free Parking jackpot under
+// that house rule, and reports whether it was paid on the spot.
+func (s *GameState) payFee(p *player.Player, amount int) bool {
+	return s.owe(Debt{Debtor: p.ID, Creditor: s.feeCreditor(), Amount: amount})
+}
+
+// feeCreditor returns where taxes and fines are paid.
+func (s *GameState) feeCreditor() int {
+	if s.Rules.FreeParkingJackpot {
+		return FreeParking
+	}
+	return Bank
+}
+
+// owe pays d at once if the debtor has the cash and reports whether it
+// did; otherwise d is left outstanding.
+func (s *GameState) owe(d Debt) bool {
+	debtor := s.Players[d.Debtor]
+	if debtor.Money >= d.Amount {
+		debtor.Pay(d.Amount)
+		s.credit(d.Creditor, d.Amount)
+		return true
+	}
+	s.Debts = append(s.Debts, d)
+	s.emit(DebtIncurred{Player: d.Debtor, Creditor: d.Creditor, Amount: d.Amount})
+	return false
+}
+
+// credit pays money owed into its destination.
+func (s *GameState) credit(creditor, amount int) {
+	switch creditor {
+	case Bank:
+	case FreeParking:
+		s.Jackpot += amount
+	default:
+		s.Players[creditor].Receive(amount)
+	}
+}
+
+// settleDebt pays the first outstanding debt.
+func (s *GameState) settleDebt() {
+	d := s.Debts[0]
+	s.Debts = s.Debts[1:]
+	s.Players[d.Debtor].Pay(d.Amount)
+	s.credit(d.Creditor, d.Amount)
+	if d.Rent {
+		s.emit(RentPaid{From: d.Debtor, To: d.Creditor, Amount: d.Amount, Space: d.Space})
+		return
+	}
+	s.emit(DebtPaid{Player: d.Debtor, Creditor: d.Creditor, Amount: d.Amount})
+}
+
+// concede declares the debtor of the first outstanding debt bankrupt to its
+// creditor. Anything else they owed goes unpaid.
+func (s *GameState) concede() {
+	d := s.Debts[0]
+	var creditor *player.Player
+	if d.Creditor >= 0 {
+		creditor = s.Players[d.Creditor]
+	}
+	s.declareBankruptcy(s.Players[d.Debtor], creditor)
+	if d.Debtor == s.Current {
+		// Whatever the turn was waiting on is moot; it only remains to end it
+		s.DebtReturnPhase = PhasePostAction
+	}
+
+	remaining := s.Debts[:0]
+	for _, other := range s.Debts[1:] {
+		if other.Debtor != d.Debtor {
+			remaining = append(remaining, other)
+		}
+	}
+	s.Debts = remaining
+}
+
+// updateDebts runs after every action. It enters the debt phase while a
+// debt is outstanding, declares a debtor bankrupt once they have nothing
+// left to sell or mortgage, and resumes the turn when all are settled.
+func (s *GameState) updateDebts() {
+	if s.Phase == PhaseTradeResponse || s.Phase == PhaseTransfer {
+		// Back to the debt once the offer or transfer is dealt with
+		return
+	}
+	for len(s.Debts) > 0 {
+		if s.Phase != PhaseDebt {
+			s.DebtReturnPhase = s.Phase
+			s.Phase = PhaseDebt
+		}
+		d := s.Debts[0]
+		debtor := s.Players[d.Debtor]
+		if debtor.Money >= d.Amount || s.canRaise(debtor) {
+			return
+		}
+		s.concede()
+	}
+	if s.Phase == PhaseDebt {
+		s.Phase = s.DebtReturnPhase
+	}
+}
+
+// canRaise reports whether p still has a house to sell or a lot to mortgage.
+func (s *GameState) canRaise(p *player.Player) bool {
+	for _, idx := range p.Properties {
+		prop := s.Board.Properties[idx]
+		if s.CanSellHouseOnSpace(idx) || (!prop.Mortgaged && prop.Houses == 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+)
+
+// landOnRent has player 0, holding money and the lots at spaces, roll onto
+// Mosquee Hassan II while player 1 owns the dark blue group.
+func landOnRent(t *testing.T, s *GameState, money int, spaces ...int) {
+	t.Helper()
+	own(s, 1, 37, 39)
+	own(s, 0, spaces...)
+	s.Players[0].Money = money
+	s.Players[0].Position = 35
+	loadDice(t, s, 1, 3)
+	apply(t, s, RollDice{})
+}
+
+func TestRentDebtSettled(t *testing.T) {
+	s := newGame(3)
+	events := record(s)
+	landOnRent(t, s, 50, 5)
+	rent := s.CalculateRent(39)
+	expectPhase(t, s, PhaseDebt, 0)
+	if d := s.Debts[0]; !d.Rent || d.Space != 39 || d.Creditor != 1 || d.Amount != rent {
+		t.Fatalf("debt %+v, want rent of %d to player 1", d, rent)
+	}
+	if count[RentPaid](*events) != 0 {
+		t.Error("rent reported paid before the money changed hands")
+	}
+	if s.IsLegal(0, PayDebt{}) || s.IsLegal(0, EndTurn{}) {
+		t.Error("the debt can be paid or skipped without raising the cash")
+	}
+
+	apply(t, s, Mortgage{Space: 5})
+	expectPhase(t, s, PhaseDebt, 0)
+	apply(t, s, PayDebt{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if s.Players[0].Money != 50+100-rent || s.Players[1].Money != 1500+rent {
+		t.Errorf("money %d and %d after paying the rent", s.Players[0].Money, s.Players[1].Money)
+	}
+	if count[RentPaid](*events) != 1 || count[DebtPaid](*events) != 0 {
+		t.Error("settled rent not reported as rent paid")
+	}
+}
+
+func TestBankruptcyToPlayer(t *testing.T) {
+	s := newGame(3)
+	landOnRent(t, s, 50, 5, 15)
+	s.Board.Properties[15].Mortgaged = true
+	s.Players[0].GetOutOfJailCards = 1
+
+	apply(t, s, DeclareBankruptcy{})
+	if p := s.Players[0]; !p.Bankrupt || p.Money != 0 || len(p.Properties) != 0 {
+		t.Fatal("the debtor is not bankrupt")
+	}
+	creditor := s.Players[1]
+	if creditor.Money != 1550 || s.Board.Properties[5].OwnerID != 1 || creditor.GetOutOfJailCards != 1 {
+		t.Error("the creditor did not receive the debtor's assets")
+	}
+
+	// The mortgaged lot is the creditor's to decide on before the turn ends
+	expectPhase(t, s, PhaseTransfer, 1)
+	apply(t, s, ReceiveMortgaged{})
+	expectPhase(t, s, PhasePostAction, 0)
+	apply(t, s, EndTurn{})
+	expectPhase(t, s, PhasePreRoll, 1)
+}
+
+func TestBankruptcySellsBuildings(t *testing.T) {
+	s := newGame(3)
+	s.Board.Properties[1].Houses = config.HotelLevel
+	s.Board.Properties[3].Houses = 2
+	s.Board.HotelPool--
+	s.Board.HousePool -= 2
+	landOnRent(t, s, 0, 1, 3)
+
+	apply(t, s, DeclareBankruptcy{})
+	if s.Board.Properties[1].Houses != 0 || s.Board.Properties[3].Houses != 0 {
+		t.Fatal("the creditor received the buildings")
+	}
+	if s.Board.HotelPool != config.MaxHotels || s.Board.HousePool != config.MaxHouses {
+		t.Error("the buildings did not go back to the bank")
+	}
+	if s.Players[1].Money != 1500+25*7 {
+		t.Errorf("creditor has %d, want the buildings' half price", s.Players[1].Money)
+	}
+}
+
+func TestBankruptcyWhenNothingLeft(t *testing.T) {
+	s := newGame(3)
+	landOnRent(t, s, 10, 1)
+	apply(t, s, Mortgage{Space: 1})
+
+	// 40 is still short of the rent, with nothing left to raise it
+	if !s.Players[0].Bankrupt || s.Board.Properties[1].OwnerID != 1 {
+		t.Fatal("a debtor with nothing left to sell was not made bankrupt")
+	}
+	expectPhase(t, s, PhaseTransfer, 1)
+}
+
+func TestLastDebtorStandingWins(t *testing.T) {
+	s := newGame(2)
+	landOnRent(t, s, 50)
+	if !s.IsOver() || s.LegalActions(1) != nil {
+		t.Error("the game went on with one player left")
+	}
+}
+
+func TestForcedJailFineOwed(t *testing.T) {
+	s := newGame(2)
+	events := record(s)
+	own(s, 0, 1)
+	p := s.Players[0]
+	p.InJail, p.JailTurns, p.Position, p.Money = true, config.MaxJailTurns-1, config.JailPosition, 10
+	s.StartTurn()
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+
+	expectPhase(t, s, PhaseDebt, 0)
+	var left []LeftJail
+	for _, e := range *events {
+		if e, ok := e.(LeftJail); ok {
+			left = append(left, e)
+		}
+	}
+	if len(left) != 1 || left[0].By != JailExitOwingFine {
+		t.Errorf("left jail %+v, want owing the fine", left)
+	}
+	if p.InJail || p.Position != config.JailPosition+3 {
+		t.Error("the player did not leave jail and move")
+	}
+}
@@ -0,0 +1,179 @@
+// Package engine implements the Monopoly rules without any rendering or
+// audio dependencies. A GameState is advanced exclusively through Apply and
+// reports what happened as typed events to its subscribers.
+package engine
+
+import (
+	"slices"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// GameState holds the complete rules state of a game in progress.
+type GameState struct {
+	Board   *board.Board
+	Players []*player.Player
+	Current int // index of current player
+	Phase   TurnPhase
+	Round   int // 1-based; goes up each time play passes the first seat
+	Rules   RuleSet
+	Jackpot int // Free Parking pot, under that house rule
+
+	// Dice
+	Die1, Die2   int
+	Doubles      bool
+	DoublesCount int
+
+	// Auction state
+	AuctionSpaceIdx    int
+	AuctionActive      [config.MaxPlayers]bool
+	AuctionCurrent     int
+	AuctionHighBid     int
+	AuctionHighBidder  int
+	AuctionQueue       []int     // properties handed back to the bank, auctioned next
+	AuctionReturnPhase TurnPhase // phase to resume once the auction is over
+
+	// Trade awaiting the partner's answer
+	PendingOffer     *TradeOffer
+	TradeReturnPhase TurnPhase // phase to resume once the offer is answered
+	TradesProposed   int       // offers the current player has made this turn
+
+	// Debts owed but not yet paid, settled in order
+	Debts           []Debt
+	DebtReturnPhase TurnPhase // phase to resume once every debt is settled
+
+	// Mortgaged properties received and awaiting their new owner's decision
+	Transfers           []Transfer
+	TransferReturnPhase TurnPhase
+
+	// Source of every random outcome: dice, deck shuffles, AI choices
+	Rand *rng.Rand
+
+	subscribers []func(Event)
+}
+
+// New creates a game on a fresh board with the given players, ready for the
+// first player's turn, each holding the starting money of rules. All
+// randomness is drawn from r, so the same seed and the same actions
+// reproduce the same game.
+func New(players []*player.Player, r *rng.Rand, rules RuleSet) *GameState {
+	s := &GameState{
+		Board:             board.NewBoard(r),
+		Players:           players,
+		Rand:              r,
+		Round:             1,
+		Rules:             rules,
+		AuctionHighBidder: -1,
+	}
+	for _, p := range players {
+		p.Money = rules.StartingMoney
+	}
+	s.StartTurn()
+	return s
+}
+
+// StartTurn puts the current player at the start of their turn, offering
+// jail options if they are in jail.
+func (s *GameState) StartTurn() {
+	p := s.CurrentPlayer()
+	if p == nil {
+		return
+	}
+	s.Phase = PhasePreRoll
+	s.TradesProposed = 0
+	if p.InJail {
+		s.Phase = PhaseJailDecision
+	}
+	s.emit(TurnStarted{Player: p.ID})
+}
+
+// CurrentPlayer returns the active player.
+func (s *GameState) CurrentPlayer() *player.Player {
+	if len(s.Players) == 0 {
+		return nil
+	}
+	return s.Players[s.Current]
+}
+
+// Actor returns the ID of the player whose decision the game is waiting on.
+// This is the current player except during auctions, trade responses,
+// debts and mortgaged transfers.
+func (s *GameState) Actor() int {
+	switch s.Phase {
+	case PhaseTransfer:
+		if len(s.Transfers) > 0 {
+			return s.Transfers[0].Player
+		}
+	case PhaseDebt:
+		if len(s.Debts) > 0 {
+			return s.Debts[0].Debtor
+		}
+	case PhaseAuction:
+		return s.AuctionCurrent
+	case PhaseTradeResponse:
+		if s.PendingOffer != nil {
+			return s.PendingOffer.ToPlayer
+		}
+	}
+	return s.Current
+}
+
+// Clone returns an independent copy of the game that draws its dice and
+// deck shuffles from r and has no subscribers, for playing ahead without
+// touching the real game.
+func (s *GameState) Clone(r *rng.Rand) *GameState {
+	c := *s
+	c.Board = s.Board.Clone(r)
+	c.Rand = r
+	c.subscribers = nil
+	c.Players = make([]*player.Player, len(s.Players))
+	for i, p := range s.Players {
+		cp := *p
+		cp.Properties = slices.Clone(p.Properties)
+		c.Players[i] = &cp
+	}
+	c.AuctionQueue = slices.Clone(s.AuctionQueue)
+	if s.PendingOffer != nil {
+		offer := *s.PendingOffer
+		offer.OfferedProps = slices.Clone(offer.OfferedProps)
+		offer.WantedProps = slices.Clone(offer.WantedProps)
+		c.PendingOffer = &offer
+	}
+	c.Debts = slices.Clone(s.Debts)
+	c.Transfers = slices.Clone(s.Transfers)
+	return &c
+}
+
+// AlivePlayers returns all non-bankrupt players.
+func (s *GameState) AlivePlayers() []*player.Player {
+	var alive []*player.Player
+	for _, p := range s.Players {
+		if !p.Bankrupt {
+			alive = append(alive, p)
+		}
+	}
+	return alive
+}
+
+// IsOver reports whether at most one player is left standing.
+func (s *GameState) IsOver() bool {
+	return len(s.AlivePlayers()) <= 1
+}
+
+// nextPlayer advances to the next non-bankrupt player.
+func (s *GameState) nextPlayer() {
+	prev := s.Current
+	for {
+		s.Current = (s.Current + 1) % len(s.Players)
+		if !s.Players[s.Current].Bankrupt {
+			break
+		}
+	}
+	if s.Current <= prev {
+		s.Round++
+	}
+	s.DoublesCount = 0
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// newGame starts a standard game between n human players.
+func newGame(n int) *GameState {
+	var players []*player.Player
+	for i := 0; i < n; i++ {
+		players = append(players, player.NewPlayer(i, string(rune('A'+i)), false))
+	}
+	return New(players, rng.New(1), StandardRules())
+}
+
+// own gives player id the lots at spaces.
+func own(s *GameState, id int, spaces ...int) {
+	for _, idx := range spaces {
+		s.Players[id].AddProperty(idx)
+		s.Board.Properties[idx].OwnerID = id
+	}
+}
+
+// loadDice makes the next roll come up d1 and d2.
+func loadDice(t *testing.T, s *GameState, d1, d2 int) {
+	t.Helper()
+	r := rng.New(0)
+	for state := uint64(0); state < 10000; state++ {
+		r.SetState(state)
+		if r.Intn(6)+1 == d1 && r.Intn(6)+1 == d2 {
+			s.Rand.SetState(state)
+			return
+		}
+	}
+	t.Fatalf("no roll of %d and %d found", d1, d2)
+}
+
+// apply applies a for whoever the game is waiting on.
+func apply(t *testing.T, s *GameState, a Action) {
+	t.Helper()
+	if err := s.Apply(s.Actor(), a); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// expectPhase checks the game is waiting on player id in phase.
+func expectPhase(t *testing.T, s *GameState, phase TurnPhase, id int) {
+	t.Helper()
+	if s.Phase != phase || s.Actor() != id {
+		t.Fatalf("waiting on player %d in %s, want player %d in %s", s.Actor(), s.Phase, id, phase)
+	}
+}
+
+// record collects the events s emits from now on.
+func record(s *GameState) *[]Event {
+	var events []Event
+	s.Subscribe(func(e Event) { events = append(events, e) })
+	return &events
+}
+
+// count returns how many of events are of type E.
+func count[E Event](events []Event) int {
+	n := 0
+	for _, e := range events {
+		if _, ok := e.(E); ok {
+			n++
+		}
+	}
+	return n
+}
+
+func TestClone(t *testing.T) {
+	s := newGame(2)
+	own(s, 0, 1)
+	c := s.Clone(rng.New(7))
+	loadDice(t, c, 1, 2)
+	apply(t, c, RollDice{})
+	apply(t, c, BuyProperty{})
+
+	if s.Players[0].Position != 0 || s.Players[0].Money != 1500 || s.Board.Properties[3].OwnerID != -1 {
+		t.Error("playing the clone changed the game")
+	}
+	if len(s.Players[0].Properties) != 1 {
+		t.Errorf("game lists %v, want only the lot it owned", s.Players[0].Properties)
+	}
+}
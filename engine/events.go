@@ -0,0 +1,375 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+)
+
+// Bank stands in for a player ID when money comes from or goes to the bank.
+const Bank = -1
+
+// FreeParking stands in for a player ID when money goes into the Free
+// Parking jackpot.
+const FreeParking = -2
+
+// Event is a fact about something that happened in the game. Events are
+// delivered to subscribers in the order they happen.
+//
+// Payments are reported when the money changes hands. One the payer cannot
+// cover is reported as a DebtIncurred, then when settled as a DebtPaid, or
+// a RentPaid for rent; a debt conceded in bankruptcy is never reported as
+// paid.
+type Event interface {
+	isEvent()
+}
+
+// JailReason says why a player was sent to jail.
+type JailReason int
+
+const (
+	JailSpace        JailReason = iota // landed on Go To Jail
+	JailCard                           // drew a Go To Jail card
+	JailThreeDoubles                   // rolled three doubles in a row
+)
+
+// JailExit says how a player got out of jail.
+type JailExit int
+
+const (
+	JailExitDoubles    JailExit = iota // rolled doubles
+	JailExitFine                       // paid the fine before rolling
+	JailExitCard                       // used a Get Out of Jail Free card
+	JailExitForcedFine                 // paid the fine after the last allowed turn
+	JailExitOwingFine                  // left after the last allowed turn owing the fine
+)
+
+// Deck identifies the deck a card came from.
+type Deck int
+
+const (
+	DeckChance Deck = iota
+	DeckCommunity
+)
+
+// TurnStarted marks the start of a player's turn.
+type TurnStarted struct{ Player int }
+
+// DiceRolled reports a roll of both dice.
+type DiceRolled struct {
+	Player, Die1, Die2 int
+	Doubles            bool
+}
+
+// RollAgain reports that doubles earned the player another roll.
+type RollAgain struct{ Player int }
+
+// PassedGo reports the salary collected for passing or landing on DEPART.
+type PassedGo struct{ Player, Amount int }
+
+// Landed reports the space a player's move ended on.
+type Landed struct{ Player, Space int }
+
+// PurchaseOffered reports that the player may buy the unowned space.
+type PurchaseOffered struct{ Player, Space, Price int }
+
+// PropertyBought reports a purchase at list price.
+type PropertyBought struct{ Player, Space, Price int }
+
+// PurchaseDeclined reports that the player passed on buying the space.
+type PurchaseDeclined struct{ Player, Space int }
+
+// RentPaid reports rent paid from one player to a space's owner.
+type RentPaid struct{ From, To, Amount, Space int }
+
+// RentWaived reports landing on an opponent's mortgaged property, or on
+// one whose owner is in jail under the house rule.
+type RentWaived struct {
+	Player, Space int
+	OwnerJailed   bool
+}
+
+// TaxPaid reports a tax charged on a tax space.
+type TaxPaid struct {
+	Player, Space, Amount int
+	Percent               bool // income tax paid as 10% of net worth
+}
+
+// CardDrawn reports a Chance or Caisse Commune card.
+type CardDrawn struct {
+	Player int
+	Deck   Deck
+	Card   board.Card
+}
+
+// Collected reports money a card paid to a player, from the bank or
+// another player.
+type Collected struct{ Player, From, Amount int }
+
+// Paid reports money a card made a player pay, to the bank or another player.
+type Paid struct{ Player, To, Amount int }
+
+// RepairsPaid reports a per-house repair card.
+type RepairsPaid struct{ Player, Amount, Houses, Hotels int }
+
+// JailCardReceived reports a Get Out of Jail Free card drawn.
+type JailCardReceived struct{ Player int }
+
+// WentToJail reports a player sent to jail.
+type WentToJail struct {
+	Player int
+	Reason JailReason
+}
+
+// LeftJail reports a player released from jail.
+type LeftJail struct {
+	Player int
+	By     JailExit
+}
+
+// StayedInJail reports a failed attempt to roll doubles in jail.
+type StayedInJail struct{ Player, Turns int }
+
+// HouseBuilt reports a house (or hotel) built; Houses is the new level.
+type HouseBuilt struct{ Player, Space, Houses, Cost int }
+
+// HouseSold reports a house (or hotel) sold; Houses is the new level.
+type HouseSold struct{ Player, Space, Houses, Refund int }
+
+// Mortgaged reports a property mortgaged for Amount.
+type Mortgaged struct{ Player, Space, Amount int }
+
+// Unmortgaged reports a mortgage lifted for Amount.
+type Unmortgaged struct{ Player, Space, Amount int }
+
+// AuctionStarted reports a property put up for auction.
+type AuctionStarted struct{ Space int }
+
+// BidPlaced reports a raise in the current auction.
+type BidPlaced struct{ Player, Amount int }
+
+// AuctionPassed reports a bidder dropping out of the auction.
+type AuctionPassed struct{ Player int }
+
+// AuctionWon reports the winning bid.
+type AuctionWon struct{ Player, Space, Price int }
+
+// AuctionUnsold reports an auction that ended without bids.
+type AuctionUnsold struct{ Space int }
+
+// TradeProposed reports an offer put to its recipient.
+type TradeProposed struct{ Offer TradeOffer }
+
+// TradeDeclined reports an offer turned down.
+type TradeDeclined struct{ Offer TradeOffer }
+
+// TradeExecuted reports an accepted offer carried out.
+type TradeExecuted struct{ Offer TradeOffer }
+
+// InterestPaid reports the interest paid to keep a mortgaged property
+// received by trade or bankruptcy mortgaged.
+type InterestPaid struct{ Player, Space, Amount int }
+
+// DebtIncurred reports a payment the player could not cover in cash. It
+// stands until they raise the money or go bankrupt; Creditor may be Bank or
+// FreeParking.
+type DebtIncurred struct{ Player, Creditor, Amount int }
+
+// DebtPaid reports an outstanding debt settled, other than rent.
+type DebtPaid struct{ Player, Creditor, Amount int }
+
+// JackpotWon reports the Free Parking pot collected.
+type JackpotWon struct{ Player, Amount int }
+
+// Bankrupt reports a player eliminated; Creditor receives their assets, or
+// is Bank.
+type Bankrupt struct{ Player, Creditor int }
+
+func (TurnStarted) isEvent()      {}
+func (DiceRolled) isEvent()       {}
+func (RollAgain) isEvent()        {}
+func (PassedGo) isEvent()         {}
+func (Landed) isEvent()           {}
+func (PurchaseOffered) isEvent()  {}
+func (PropertyBought) isEvent()   {}
+func (PurchaseDeclined) isEvent() {}
+func (RentPaid) isEvent()         {}
+func (RentWaived) isEvent()       {}
+func (TaxPaid) isEvent()          {}
+func (CardDrawn) isEvent()        {}
+func (Collected) isEvent()        {}
+func (Paid) isEvent()             {}
+func (RepairsPaid) isEvent()      {}
+func (JailCardReceived) isEvent() {}
+func (WentToJail) isEvent()       {}
+func (LeftJail) isEvent()         {}
+func (StayedInJail) isEvent()     {}
+func (HouseBuilt) isEvent()       {}
+func (HouseSold) isEvent()        {}
+func (Mortgaged) isEvent()        {}
+func (Unmortgaged) isEvent()      {}
+func (AuctionStarted) isEvent()   {}
+func (BidPlaced) isEvent()        {}
+func (AuctionPassed) isEvent()    {}
+func (AuctionWon) isEvent()       {}
+func (AuctionUnsold) isEvent()    {}
+func (TradeProposed) isEvent()    {}
+func (TradeDeclined) isEvent()    {}
+func (TradeExecuted) isEvent()    {}
+func (InterestPaid) isEvent()     {}
+func (DebtIncurred) isEvent()     {}
+func (DebtPaid) isEvent()         {}
+func (JackpotWon) isEvent()       {}
+func (Bankrupt) isEvent()         {}
+
+// Subscribe registers fn to receive every event from now on, synchronously
+// and in order, while the action that caused it is being applied.
+func (s *GameState) Subscribe(fn func(Event)) {
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// emit delivers an event to all subscribers.
+func (s *GameState) emit(e Event) {
+	for _, fn := range s.subscribers {
+		fn(e)
+	}
+}
+
+// PartyName names the player with the given ID, or the bank or the Free
+// Parking pot for Bank and FreeParking.
+func (s *GameState) PartyName(id int) string {
+	switch id {
+	case Bank:
+		return "the bank"
+	case FreeParking:
+		return "the Free Parking pot"
+	}
+	return s.Players[id].Name
+}
+
+// Describe renders an event as a one-line log message, or "" for events
+// that are not worth logging.
+func (s *GameState) Describe(e Event) string {
+	name := s.PartyName
+	space := func(idx int) string { return s.Board.Spaces[idx].Name }
+
+	switch e := e.(type) {
+	case TurnStarted:
+		return fmt.Sprintf("--- %s's turn ---", name(e.Player))
+	case DiceRolled:
+		msg := fmt.Sprintf("%s rolled %d + %d = %d", name(e.Player), e.Die1, e.Die2, e.Die1+e.Die2)
+		if e.Doubles {
+			msg += " - Doubles!"
+		}
+		return msg
+	case RollAgain:
+		return fmt.Sprintf("%s rolls again (doubles)", name(e.Player))
+	case PassedGo:
+		return fmt.Sprintf("%s passed GO! +%d MAD", name(e.Player), e.Amount)
+	case Landed:
+		msg := fmt.Sprintf("%s landed on %s", name(e.Player), space(e.Space))
+		switch s.Board.Spaces[e.Space].Type {
+		case board.SpaceJail:
+			msg += " - just visiting"
+		case board.SpaceFreeParking:
+			if !s.Rules.FreeParkingJackpot {
+				msg += " - nothing happens"
+			}
+		}
+		return msg
+	case PurchaseOffered:
+		return fmt.Sprintf("Buy %s for %d MAD?", space(e.Space), e.Price)
+	case PropertyBought:
+		return fmt.Sprintf("%s bought %s for %d MAD", name(e.Player), space(e.Space), e.Price)
+	case PurchaseDeclined:
+		return fmt.Sprintf("%s declined to buy", name(e.Player))
+	case RentPaid:
+		return fmt.Sprintf("%s pays %d MAD rent to %s", name(e.From), e.Amount, name(e.To))
+	case RentWaived:
+		if e.OwnerJailed {
+			return fmt.Sprintf("%s is in jail - no rent", name(s.Board.Properties[e.Space].OwnerID))
+		}
+		return fmt.Sprintf("%s is mortgaged - no rent", space(e.Space))
+	case TaxPaid:
+		if e.Percent {
+			return fmt.Sprintf("%s pays %d MAD income tax (10%%)", name(e.Player), e.Amount)
+		}
+		return fmt.Sprintf("%s pays %d MAD tax", name(e.Player), e.Amount)
+	case CardDrawn:
+		if e.Deck == DeckChance {
+			return "Chance: " + e.Card.Text
+		}
+		return "Caisse: " + e.Card.Text
+	case Collected:
+		return fmt.Sprintf("%s receives %d MAD from %s", name(e.Player), e.Amount, name(e.From))
+	case Paid:
+		return fmt.Sprintf("%s pays %d MAD to %s", name(e.Player), e.Amount, name(e.To))
+	case RepairsPaid:
+		return fmt.Sprintf("%s pays %d MAD (%d houses, %d hotels)", name(e.Player), e.Amount, e.Houses, e.Hotels)
+	case JailCardReceived:
+		return fmt.Sprintf("%s gets a Get Out of Jail Free card!", name(e.Player))
+	case WentToJail:
+		if e.Reason == JailThreeDoubles {
+			return fmt.Sprintf("%s: 3 doubles! Go to jail!", name(e.Player))
+		}
+		return fmt.Sprintf("%s goes to jail!", name(e.Player))
+	case LeftJail:
+		switch e.By {
+		case JailExitDoubles:
+			return fmt.Sprintf("%s rolled doubles and is free!", name(e.Player))
+		case JailExitFine:
+			return fmt.Sprintf("%s paid %d MAD to get out of jail", name(e.Player), s.Rules.JailFine)
+		case JailExitCard:
+			return fmt.Sprintf("%s used Get Out of Jail Free card", name(e.Player))
+		case JailExitOwingFine:
+			return fmt.Sprintf("%s leaves jail owing the %d MAD fine", name(e.Player), s.Rules.JailFine)
+		default:
+			return fmt.Sprintf("%s paid %d MAD jail fine (forced)", name(e.Player), s.Rules.JailFine)
+		}
+	case StayedInJail:
+		return fmt.Sprintf("%s stays in jail (%d/%d turns)", name(e.Player), e.Turns, s.Rules.MaxJailTurns)
+	case HouseBuilt:
+		level := fmt.Sprintf("%d house(s)", e.Houses)
+		if e.Houses == config.HotelLevel {
+			level = "hotel"
+		}
+		return fmt.Sprintf("%s built on %s (%s, -%d MAD)", name(e.Player), space(e.Space), level, e.Cost)
+	case HouseSold:
+		return fmt.Sprintf("%s sold house on %s (+%d MAD)", name(e.Player), space(e.Space), e.Refund)
+	case Mortgaged:
+		return fmt.Sprintf("%s mortgaged %s (+%d MAD)", name(e.Player), space(e.Space), e.Amount)
+	case Unmortgaged:
+		return fmt.Sprintf("%s unmortgaged %s (-%d MAD)", name(e.Player), space(e.Space), e.Amount)
+	case AuctionStarted:
+		return fmt.Sprintf("Auction started for %s!", space(e.Space))
+	case BidPlaced:
+		return fmt.Sprintf("%s bids %d MAD", name(e.Player), e.Amount)
+	case AuctionPassed:
+		return fmt.Sprintf("%s passes", name(e.Player))
+	case AuctionWon:
+		return fmt.Sprintf("%s wins auction for %s at %d MAD!", name(e.Player), space(e.Space), e.Price)
+	case AuctionUnsold:
+		return fmt.Sprintf("No bids! %s remains unowned.", space(e.Space))
+	case TradeProposed:
+		return fmt.Sprintf("%s proposes a trade to %s", name(e.Offer.FromPlayer), name(e.Offer.ToPlayer))
+	case TradeDeclined:
+		return fmt.Sprintf("%s declined the trade", name(e.Offer.ToPlayer))
+	case TradeExecuted:
+		return fmt.Sprintf("Trade completed between %s and %s", name(e.Offer.FromPlayer), name(e.Offer.ToPlayer))
+	case InterestPaid:
+		return fmt.Sprintf("%s keeps %s mortgaged (-%d MAD interest)", name(e.Player), space(e.Space), e.Amount)
+	case DebtIncurred:
+		return fmt.Sprintf("%s owes %s %d MAD and must raise cash!", name(e.Player), name(e.Creditor), e.Amount)
+	case DebtPaid:
+		return fmt.Sprintf("%s paid %s %d MAD", name(e.Player), name(e.Creditor), e.Amount)
+	case JackpotWon:
+		return fmt.Sprintf("%s wins the Free Parking pot! +%d MAD", name(e.Player), e.Amount)
+	case Bankrupt:
+		if e.Creditor == Bank {
+			return fmt.Sprintf("%s is BANKRUPT!", name(e.Player))
+		}
+		return fmt.Sprintf("%s is BANKRUPT! %s receives all assets", name(e.Player), name(e.Creditor))
+	}
+	return ""
+}
@@ -0,0 +1,389 @@
+package engine
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// CanBuildOnGroup checks if a player can build on a colour group.
+// Requires: owns all properties in group, none mortgaged, even building rule.
+func (s *GameState) CanBuildOnGroup(playerID int, group board.ColorGroup) bool {
+	if group == board.GroupNone {
+		return false
+	}
+	if !s.HasMonopoly(playerID, group) {
+		return false
+	}
+	spaces := s.Board.SpacesInGroup(group)
+	for _, idx := range spaces {
+		if s.Board.Properties[idx].Mortgaged {
+			return false
+		}
+	}
+	return true
+}
+
+// CanBuildOnSpace checks if a house/hotel can be built on a specific property.
+// Even building rule (unless turned off): difference between min and max
+// houses in group <= 1.
+func (s *GameState) CanBuildOnSpace(spaceIndex int) bool {
+	space := s.Board.Spaces[spaceIndex]
+	prop := s.Board.Properties[spaceIndex]
+
+	if space.Type != board.SpaceProperty {
+		return false
+	}
+	if prop.OwnerID < 0 || prop.Mortgaged {
+		return false
+	}
+	if !s.CanBuildOnGroup(prop.OwnerID, space.Group) {
+		return false
+	}
+	if prop.Houses >= config.HotelLevel {
+		return false
+	}
+
+	// Check pool availability
+	if prop.Houses == config.HousesPerHotel {
+		// Upgrading to hotel
+		if s.Board.HotelPool <= 0 {
+			return false
+		}
+	} else {
+		if s.Board.HousePool <= 0 {
+			return false
+		}
+	}
+
+	// Even building rule: this property must have the fewest houses in its group
+	minHouses := s.minHousesInGroup(space.Group)
+	return prop.Houses <= minHouses || !s.Rules.EvenBuild
+}
+
+// BuildHouse adds a house (or hotel) to a property.
+func (s *GameState) BuildHouse(spaceIndex int) int {
+	space := s.Board.Spaces[spaceIndex]
+	prop := &s.Board.Properties[spaceIndex]
+
+	if prop.Houses == config.HousesPerHotel {
+		// Upgrade to hotel: return 4 houses to pool, take 1 hotel
+		s.Board.HousePool += config.HousesPerHotel
+		s.Board.HotelPool--
+		prop.Houses = config.HotelLevel
+	} else {
+		s.Board.HousePool--
+		prop.Houses++
+	}
+
+	return space.HouseCost
+}
+
+// SellHouse removes a house from a property (even sell-down rule).
+func (s *GameState) CanSellHouseOnSpace(spaceIndex int) bool {
+	space := s.Board.Spaces[spaceIndex]
+	prop := s.Board.Properties[spaceIndex]
+
+	if space.Type != board.SpaceProperty {
+		return false
+	}
+	if prop.Houses <= 0 {
+		return false
+	}
+	if prop.Houses == config.HotelLevel && s.Board.HousePool < config.HousesPerHotel {
+		// Breaking a hotel takes its houses back from the bank
+		return false
+	}
+
+	// Even selling rule: this property must have the most houses in its group
+	maxHouses := s.maxHousesInGroup(space.Group)
+	return prop.Houses >= maxHouses || !s.Rules.EvenBuild
+}
+
+// SellHouse removes a house and returns half the house cost.
+func (s *GameState) SellHouse(spaceIndex int) int {
+	space := s.Board.Spaces[spaceIndex]
+	prop := &s.Board.Properties[spaceIndex]
+
+	if prop.Houses == config.HotelLevel {
+		// Downgrade from hotel: return 1 hotel, take 4 houses
+		s.Board.HotelPool++
+		s.Board.HousePool -= config.HousesPerHotel
+		prop.Houses = config.HousesPerHotel
+	} else {
+		s.Board.HousePool++
+		prop.Houses--
+	}
+
+	return space.HouseCost / 2
+}
+
+// BuildableProperties returns all space indices where the player can build.
+func (s *GameState) BuildableProperties(playerID int) []int {
+	var result []int
+	for _, idx := range s.Players[playerID].Properties {
+		if s.CanBuildOnSpace(idx) {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
+
+// MortgageableProperties returns properties that can be mortgaged.
+func (s *GameState) MortgageableProperties(playerID int) []int {
+	var result []int
+	for _, idx := range s.Players[playerID].Properties {
+		prop := s.Board.Properties[idx]
+		if !prop.Mortgaged && prop.Houses == 0 {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
+
+// UnmortgageableProperties returns mortgaged properties that can be unmortgaged.
+func (s *GameState) UnmortgageableProperties(playerID int) []int {
+	var result []int
+	for _, idx := range s.Players[playerID].Properties {
+		prop := s.Board.Properties[idx]
+		if prop.Mortgaged {
+			cost := s.UnmortgageCost(idx)
+			if s.Players[playerID].Money >= cost {
+				result = append(result, idx)
+			}
+		}
+	}
+	return result
+}
+
+// CanTradeProperty reports whether a property may change hands in a trade:
+// it must have no houses. A mortgaged one costs its receiver interest or an
+// immediate unmortgage.
+func (s *GameState) CanTradeProperty(spaceIndex int) bool {
+	return s.Board.Properties[spaceIndex].Houses == 0
+}
+
+// MortgageValue returns cash received for mortgaging.
+func (s *GameState) MortgageValue(spaceIndex int) int {
+	return s.Board.Spaces[spaceIndex].Price * s.Rules.MortgageRate / 100
+}
+
+// UnmortgageCost returns cost to unmortgage.
+func (s *GameState) UnmortgageCost(spaceIndex int) int {
+	mortgageVal := s.MortgageValue(spaceIndex)
+	return mortgageVal + mortgageVal*s.Rules.InterestRate/100
+}
+
+// MortgageProperty mortgages a property.
+func (s *GameState) MortgageProperty(spaceIndex int) int {
+	s.Board.Properties[spaceIndex].Mortgaged = true
+	return s.MortgageValue(spaceIndex)
+}
+
+// UnmortgageProperty unmortgages a property.
+func (s *GameState) UnmortgageProperty(spaceIndex int) int {
+	s.Board.Properties[spaceIndex].Mortgaged = false
+	return s.UnmortgageCost(spaceIndex)
+}
+
+func (s *GameState) minHousesInGroup(group board.ColorGroup) int {
+	spaces := s.Board.SpacesInGroup(group)
+	min := 999
+	for _, idx := range spaces {
+		h := s.Board.Properties[idx].Houses
+		if h < min {
+			min = h
+		}
+	}
+	return min
+}
+
+func (s *GameState) maxHousesInGroup(group board.ColorGroup) int {
+	spaces := s.Board.SpacesInGroup(group)
+	max := -1
+	for _, idx := range spaces {
+		h := s.Board.Properties[idx].Houses
+		if h > max {
+			max = h
+		}
+	}
+	return max
+}
+
+// PlayerNetWorth calculates a player's total asset value.
+func (s *GameState) PlayerNetWorth(playerID int) int {
+	p := s.Players[playerID]
+	total := p.Money
+	for _, idx := range p.Properties {
+		space := s.Board.Spaces[idx]
+		prop := s.Board.Properties[idx]
+		if prop.Mortgaged {
+			total += s.MortgageValue(idx)
+		} else {
+			total += space.Price
+		}
+		if prop.Houses > 0 && prop.Houses <= config.HousesPerHotel {
+			total += prop.Houses * space.HouseCost / 2
+		} else if prop.Houses == config.HotelLevel {
+			total += config.HousesPerHotel * space.HouseCost / 2
+		}
+	}
+	return total
+}
+
+// CalculateRent computes rent for a property.
+func (s *GameState) CalculateRent(spaceIndex int) int {
+	space := s.Board.Spaces[spaceIndex]
+	prop := s.Board.Properties[spaceIndex]
+
+	if prop.Mortgaged {
+		return 0
+	}
+
+	switch space.Type {
+	case board.SpaceProperty:
+		houses := prop.Houses
+		if houses > 0 {
+			return space.Rent[houses]
+		}
+		// Check for monopoly (doubles base rent)
+		if s.HasMonopoly(prop.OwnerID, space.Group) {
+			return space.Rent[0] * 2
+		}
+		return space.Rent[0]
+
+	case board.SpaceRailroad:
+		count := s.CountOwnedRailroads(prop.OwnerID)
+		rents := [4]int{25, 50, 100, 200}
+		if count >= 1 && count <= 4 {
+			return rents[count-1]
+		}
+		return 25
+
+	case board.SpaceUtility:
+		count := s.CountOwnedUtilities(prop.OwnerID)
+		diceTotal := s.Die1 + s.Die2
+		if count == 2 {
+			return diceTotal * 10
+		}
+		return diceTotal * 4
+	}
+
+	return 0
+}
+
+// HasMonopoly checks if a player owns all properties in a colour group.
+func (s *GameState) HasMonopoly(playerID int, group board.ColorGroup) bool {
+	if group == board.GroupNone {
+		return false
+	}
+	spaces := s.Board.SpacesInGroup(group)
+	for _, idx := range spaces {
+		if s.Board.Properties[idx].OwnerID != playerID {
+			return false
+		}
+	}
+	return true
+}
+
+// CountOwnedRailroads returns how many railroads a player owns.
+func (s *GameState) CountOwnedRailroads(playerID int) int {
+	count := 0
+	for _, idx := range s.Board.RailroadSpaces() {
+		if s.Board.Properties[idx].OwnerID == playerID {
+			count++
+		}
+	}
+	return count
+}
+
+// CountOwnedUtilities returns how many utilities a player owns.
+func (s *GameState) CountOwnedUtilities(playerID int) int {
+	count := 0
+	for _, idx := range s.Board.UtilitySpaces() {
+		if s.Board.Properties[idx].OwnerID == playerID {
+			count++
+		}
+	}
+	return count
+}
+
+// build adds a house or hotel to a property and charges the player.
+func (s *GameState) build(p *player.Player, spaceIndex int) {
+	cost := s.BuildHouse(spaceIndex)
+	p.Pay(cost)
+	s.emit(HouseBuilt{Player: p.ID, Space: spaceIndex, Houses: s.Board.Properties[spaceIndex].Houses, Cost: cost})
+}
+
+// sellHouse sells one house (or hotel level) back to the bank.
+func (s *GameState) sellHouse(p *player.Player, spaceIndex int) {
+	refund := s.SellHouse(spaceIndex)
+	p.Receive(refund)
+	s.emit(HouseSold{Player: p.ID, Space: spaceIndex, Houses: s.Board.Properties[spaceIndex].Houses, Refund: refund})
+}
+
+// mortgage mortgages a property and credits the player.
+func (s *GameState) mortgage(p *player.Player, spaceIndex int) {
+	val := s.MortgageProperty(spaceIndex)
+	p.Receive(val)
+	s.emit(Mortgaged{Player: p.ID, Space: spaceIndex, Amount: val})
+}
+
+// unmortgage lifts a mortgage and charges the player.
+func (s *GameState) unmortgage(p *player.Player, spaceIndex int) {
+	cost := s.UnmortgageProperty(spaceIndex)
+	p.Pay(cost)
+	s.emit(Unmortgaged{Player: p.ID, Space: spaceIndex, Amount: cost})
+}
+
+// declareBankruptcy eliminates a player and transfers assets.
+func (s *GameState) declareBankruptcy(debtor *player.Player, creditor *player.Player) {
+	creditorID := Bank
+	if creditor != nil {
+		creditorID = creditor.ID
+	}
+	s.emit(Bankrupt{Player: debtor.ID, Creditor: creditorID})
+	debtor.Bankrupt = true
+
+	if creditor != nil {
+		// Buildings go back to the bank at half price; the rest to the creditor
+		s.sellBuildings(debtor)
+		creditor.Receive(debtor.Money)
+		for _, idx := range append([]int(nil), debtor.Properties...) {
+			s.giveProperty(debtor, creditor, idx)
+		}
+		creditor.GetOutOfJailCards += debtor.GetOutOfJailCards
+	} else {
+		// Owed to bank — return properties to bank (unowned), auction them
+		for _, idx := range debtor.Properties {
+			s.returnToBank(idx)
+		}
+	}
+
+	debtor.Money = 0
+	debtor.Properties = nil
+	debtor.GetOutOfJailCards = 0
+}
+
+// sellBuildings sells all of p's houses and hotels back to the bank at half
+// their cost. A hotel goes back whole, so the bank need not have the houses
+// to break it.
+func (s *GameState) sellBuildings(p *player.Player) {
+	for _, idx := range p.Properties {
+		prop := &s.Board.Properties[idx]
+		if prop.Houses == 0 {
+			continue
+		}
+		refund := s.Board.Spaces[idx].HouseCost / 2
+		if prop.Houses == config.HotelLevel {
+			s.Board.HotelPool++
+			refund *= config.HousesPerHotel + 1
+		} else {
+			s.Board.HousePool += prop.Houses
+			refund *= prop.Houses
+		}
+		prop.Houses = 0
+		p.Receive(refund)
+		s.emit(HouseSold{Player: p.ID, Space: idx, Houses: 0, Refund: refund})
+	}
+}
@@ -0,0 +1,38 @@
+package engine
+
+import "github.com/AchrafSoltani/MoroccanMonopoly/config"
+
+// RuleSet holds the rules chosen when a game starts, including the common
+// house rules. StandardRules gives the rules as printed.
+type RuleSet struct {
+	StartingMoney int
+	GoSalary      int
+	JailFine      int // paid to leave jail, and forced after MaxJailTurns
+	MaxJailTurns  int // failed rolls for doubles before the fine is forced
+	MortgageRate  int // percent of a lot's price lent on a mortgage
+	InterestRate  int // percent of the loan charged to lift a mortgage or take one over
+
+	FreeParkingJackpot bool // taxes and fines go into a pot won by landing on Free Parking
+	DoubleSalary       bool // landing exactly on GO pays twice the salary
+	NoAuctions         bool // a declined property stays with the bank
+	NoRentInJail       bool // owners in jail collect no rent
+	EvenBuild          bool // houses are built and sold evenly across a group
+}
+
+// StandardRules returns the official rules.
+func StandardRules() RuleSet {
+	return RuleSet{
+		StartingMoney: config.StartingMoney,
+		GoSalary:      config.GoSalary,
+		JailFine:      config.JailFine,
+		MaxJailTurns:  config.MaxJailTurns,
+		MortgageRate:  config.MortgageRate,
+		InterestRate:  config.InterestRate,
+		EvenBuild:     true,
+	}
+}
+
+// IsStandard reports whether r plays by the official rules.
+func (r RuleSet) IsStandard() bool {
+	return r == StandardRules()
+}
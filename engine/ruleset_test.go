@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+)
+
+func TestDeclineBuyWithoutAuctions(t *testing.T) {
+	s := newGame(2)
+	s.Rules.NoAuctions = true
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+	apply(t, s, DeclineBuy{})
+	expectPhase(t, s, PhasePostAction, 0)
+	if s.Board.Properties[3].OwnerID != -1 {
+		t.Error("a declined lot was sold without an auction")
+	}
+}
+
+func TestJailTerms(t *testing.T) {
+	s := newGame(2)
+	s.Rules.JailFine, s.Rules.MaxJailTurns = 80, 1
+	p := s.Players[0]
+	p.InJail, p.Position = true, config.JailPosition
+	s.StartTurn()
+	p.Money = 70
+	if s.IsLegal(0, PayJailFine{}) {
+		t.Error("paid a fine of 80 with 70")
+	}
+	p.Money = 1500
+
+	// The one failed roll allowed forces the fine
+	loadDice(t, s, 1, 2)
+	apply(t, s, RollDice{})
+	if p.InJail || p.Money != 1500-80 || p.Position != config.JailPosition+3 {
+		t.Errorf("in jail %t with %d on space %d after the last allowed roll", p.InJail, p.Money, p.Position)
+	}
+}
+
+func TestMortgageTerms(t *testing.T) {
+	for _, tc := range []struct {
+		mortgage, interest          int
+		value, unmortgage, takeOver int
+	}{
+		{config.MortgageRate, config.InterestRate, 200, 220, 20},
+		{40, 20, 160, 192, 32},
+		{50, 0, 200, 200, 0},
+	} {
+		s := newGame(2)
+		s.Rules.MortgageRate, s.Rules.InterestRate = tc.mortgage, tc.interest
+		own(s, 0, 39)
+		apply(t, s, Mortgage{Space: 39})
+		if s.Players[0].Money != 1500+tc.value {
+			t.Errorf("%d%%: mortgaging lent %d, want %d", tc.mortgage, s.Players[0].Money-1500, tc.value)
+		}
+		if got := s.UnmortgageCost(39); got != tc.unmortgage {
+			t.Errorf("%d%% interest: unmortgaging costs %d, want %d", tc.interest, got, tc.unmortgage)
+		}
+		if got := s.TransferInterest(39); got != tc.takeOver {
+			t.Errorf("%d%% interest: taking over the mortgage costs %d, want %d", tc.interest, got, tc.takeOver)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package engine
+
+// TurnPhase represents the phase within a player's turn.
+type TurnPhase int
+
+const (
+	PhasePreRoll       TurnPhase = iota
+	PhaseJailDecision            // choosing how to exit jail
+	PhaseBuyDecision             // landed on an unowned property
+	PhaseIncomeTax               // choosing flat or 10% income tax
+	PhaseAuction                 // auction in progress
+	PhaseTradeResponse           // trade offer awaiting the partner's answer
+	PhasePostAction              // post-landing, may end turn or roll again (doubles)
+	PhaseDebt                    // raising cash to pay a debt, or conceding it
+	PhaseTransfer                // deciding on a mortgaged property received
+)
+
+// String returns a short name for the phase.
+func (p TurnPhase) String() string {
+	switch p {
+	case PhasePreRoll:
+		return "pre-roll"
+	case PhaseJailDecision:
+		return "jail decision"
+	case PhaseBuyDecision:
+		return "buy decision"
+	case PhaseIncomeTax:
+		return "income tax"
+	case PhaseAuction:
+		return "auction"
+	case PhaseTradeResponse:
+		return "trade response"
+	case PhasePostAction:
+		return "post-action"
+	case PhaseDebt:
+		return "debt"
+	case PhaseTransfer:
+		return "mortgaged transfer"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+import "github.com/AchrafSoltani/MoroccanMonopoly/config"
+
+// Stats accumulates statistics for one game from its event stream.
+// Subscribe Record to a GameState to fill it in.
+type Stats struct {
+	Turns    int
+	Rolls    int
+	Doubles  int
+	Trades   int
+	Landings [config.SpaceCount]int // moves ending on each space
+
+	// Per player, indexed by player ID
+	RentPaid     [config.MaxPlayers]int
+	RentReceived [config.MaxPlayers]int
+	Purchases    [config.MaxPlayers]int // bought outright or won at auction
+	HousesBuilt  [config.MaxPlayers]int
+	JailVisits   [config.MaxPlayers]int
+
+	Eliminated []int // player IDs in the order they went bankrupt
+}
+
+// Record updates the statistics for one event.
+func (st *Stats) Record(e Event) {
+	switch e := e.(type) {
+	case TurnStarted:
+		st.Turns++
+	case DiceRolled:
+		st.Rolls++
+		if e.Doubles {
+			st.Doubles++
+		}
+	case Landed:
+		st.Landings[e.Space]++
+	case RentPaid:
+		st.RentPaid[e.From] += e.Amount
+		st.RentReceived[e.To] += e.Amount
+	case PropertyBought:
+		st.Purchases[e.Player]++
+	case AuctionWon:
+		st.Purchases[e.Player]++
+	case HouseBuilt:
+		st.HousesBuilt[e.Player]++
+	case WentToJail:
+		st.JailVisits[e.Player]++
+	case TradeExecuted:
+		st.Trades++
+	case Bankrupt:
+		st.Eliminated = append(st.Eliminated, e.Player)
+	}
+}
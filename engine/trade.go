@@ -0,0 +1,70 @@
+package engine
+
+// TradeOffer represents a trade proposal.
+type TradeOffer struct {
+	FromPlayer       int
+	ToPlayer         int
+	OfferedProps     []int // space indices offered
+	WantedProps      []int // space indices wanted
+	OfferedMoney     int
+	WantedMoney      int
+	OfferedJailCards int
+	WantedJailCards  int
+}
+
+// proposeTrade puts an offer to its recipient and waits for their answer.
+func (s *GameState) proposeTrade(offer TradeOffer) {
+	s.PendingOffer = &offer
+	s.TradeReturnPhase = s.Phase
+	s.Phase = PhaseTradeResponse
+	s.TradesProposed++
+	s.emit(TradeProposed{Offer: offer})
+}
+
+// acceptTrade executes the pending offer.
+func (s *GameState) acceptTrade() {
+	s.executeTrade(*s.PendingOffer)
+	s.PendingOffer = nil
+	s.Phase = s.TradeReturnPhase
+}
+
+// declineTrade discards the pending offer.
+func (s *GameState) declineTrade() {
+	s.emit(TradeDeclined{Offer: *s.PendingOffer})
+	s.PendingOffer = nil
+	s.Phase = s.TradeReturnPhase
+}
+
+// executeTrade performs the trade between two players.
+func (s *GameState) executeTrade(offer TradeOffer) {
+	from := s.Players[offer.FromPlayer]
+	to := s.Players[offer.ToPlayer]
+
+	// Transfer offered properties
+	for _, idx := range offer.OfferedProps {
+		s.giveProperty(from, to, idx)
+	}
+
+	// Transfer wanted properties
+	for _, idx := range offer.WantedProps {
+		s.giveProperty(to, from, idx)
+	}
+
+	// Transfer money
+	if offer.OfferedMoney > 0 {
+		from.Pay(offer.OfferedMoney)
+		to.Receive(offer.OfferedMoney)
+	}
+	if offer.WantedMoney > 0 {
+		to.Pay(offer.WantedMoney)
+		from.Receive(offer.WantedMoney)
+	}
+
+	// Transfer jail cards
+	from.GetOutOfJailCards -= offer.OfferedJailCards
+	to.GetOutOfJailCards += offer.OfferedJailCards
+	to.GetOutOfJailCards -= offer.WantedJailCards
+	from.GetOutOfJailCards += offer.WantedJailCards
+
+	s.emit(TradeExecuted{Offer: offer})
+}
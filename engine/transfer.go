@@ -0,0 +1,75 @@
+package engine
+
+import "github.com/AchrafSoltani/MoroccanMonopoly/player"
+
+// Transfer is a mortgaged property a player has received by trade or
+// bankruptcy and not yet decided on: unmortgage it now, or pay the interest
+// and keep it mortgaged.
+type Transfer struct {
+	Player int
+	Space  int
+}
+
+// TransferInterest returns the interest due on receiving a mortgaged
+// property that stays mortgaged.
+func (s *GameState) TransferInterest(spaceIndex int) int {
+	return s.MortgageValue(spaceIndex) * s.Rules.InterestRate / 100
+}
+
+// TransferCost returns what receiving the mortgaged properties among spaces
+// costs: the interest to keep them all mortgaged, or the price of
+// unmortgaging them all at once.
+func (s *GameState) TransferCost(spaces []int) (interest, unmortgage int) {
+	for _, idx := range spaces {
+		if s.Board.Properties[idx].Mortgaged {
+			interest += s.TransferInterest(idx)
+			unmortgage += s.UnmortgageCost(idx)
+		}
+	}
+	return interest, unmortgage
+}
+
+// giveProperty hands a property to a new owner, who must decide on it
+// before play goes on if it is mortgaged.
+func (s *GameState) giveProperty(from, to *player.Player, spaceIndex int) {
+	from.RemoveProperty(spaceIndex)
+	to.AddProperty(spaceIndex)
+	s.Board.Properties[spaceIndex].OwnerID = to.ID
+	if s.Board.Properties[spaceIndex].Mortgaged {
+		s.Transfers = append(s.Transfers, Transfer{Player: to.ID, Space: spaceIndex})
+	}
+}
+
+// receiveMortgaged settles the first pending transfer.
+func (s *GameState) receiveMortgaged(unmortgage bool) {
+	t := s.Transfers[0]
+	s.Transfers = s.Transfers[1:]
+	p := s.Players[t.Player]
+	if unmortgage {
+		s.unmortgage(p, t.Space)
+		return
+	}
+	interest := s.TransferInterest(t.Space)
+	if s.payDebt(p, nil, interest) {
+		s.emit(InterestPaid{Player: p.ID, Space: t.Space, Amount: interest})
+	}
+}
+
+// updateTransfers runs after every action. It enters the transfer phase
+// while a mortgaged property is waiting on its new owner and resumes the
+// turn once all are settled.
+func (s *GameState) updateTransfers() {
+	if s.Phase == PhaseTradeResponse {
+		return
+	}
+	if len(s.Transfers) > 0 {
+		if s.Phase != PhaseTransfer {
+			s.TransferReturnPhase = s.Phase
+			s.Phase = PhaseTransfer
+		}
+		return
+	}
+	if s.Phase == PhaseTransfer {
+		s.Phase = s.TransferReturnPhase
+	}
+}
@@ -0,0 +1,50 @@
+package engine
+
+import "testing"
+
+func TestReceiveMortgaged(t *testing.T) {
+	s := newGame(2)
+	own(s, 0, 5, 15)
+	s.Board.Properties[5].Mortgaged = true
+	s.Board.Properties[15].Mortgaged = true
+
+	apply(t, s, ProposeTrade{Offer: TradeOffer{FromPlayer: 0, ToPlayer: 1, OfferedProps: []int{5, 15}}})
+	apply(t, s, AcceptTrade{})
+	expectPhase(t, s, PhaseTransfer, 1)
+
+	apply(t, s, ReceiveMortgaged{})
+	expectPhase(t, s, PhaseTransfer, 1)
+	if s.Players[1].Money != 1500-s.TransferInterest(5) || !s.Board.Properties[5].Mortgaged {
+		t.Error("keeping the lot mortgaged did not charge the interest")
+	}
+
+	apply(t, s, ReceiveMortgaged{Unmortgage: true})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if s.Board.Properties[15].Mortgaged {
+		t.Error("the lot is still mortgaged")
+	}
+}
+
+func TestInterestOwed(t *testing.T) {
+	s := newGame(2)
+	events := record(s)
+	own(s, 0, 5)
+	own(s, 1, 1)
+	s.Board.Properties[5].Mortgaged = true
+	s.Players[1].Money = 5
+
+	apply(t, s, ProposeTrade{Offer: TradeOffer{FromPlayer: 0, ToPlayer: 1, OfferedProps: []int{5}}})
+	apply(t, s, AcceptTrade{})
+	apply(t, s, ReceiveMortgaged{})
+	expectPhase(t, s, PhaseDebt, 1)
+	if count[InterestPaid](*events) != 0 || count[DebtIncurred](*events) != 1 {
+		t.Error("interest the receiver could not pay reported as paid")
+	}
+
+	apply(t, s, Mortgage{Space: 1})
+	apply(t, s, PayDebt{})
+	expectPhase(t, s, PhasePreRoll, 0)
+	if s.Players[1].Money != 5+30-s.TransferInterest(5) || count[DebtPaid](*events) != 1 {
+		t.Errorf("money %d after paying the interest owed", s.Players[1].Money)
+	}
+}
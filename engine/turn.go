@@ -0,0 +1,338 @@
+package engine
+
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+)
+
+// rollDice rolls both dice, handles doubles and jail, then moves the
+// current player and resolves the space they land on.
+func (s *GameState) rollDice() {
+	s.Die1 = s.Rand.Intn(6) + 1
+	s.Die2 = s.Rand.Intn(6) + 1
+	s.Doubles = s.Die1 == s.Die2
+	if s.Doubles {
+		s.DoublesCount++
+	}
+
+	p := s.CurrentPlayer()
+	total := s.Die1 + s.Die2
+	s.emit(DiceRolled{Player: p.ID, Die1: s.Die1, Die2: s.Die2, Doubles: s.Doubles})
+
+	// Check for 3 consecutive doubles
+	if s.DoublesCount >= 3 {
+		s.sendToJail(p, JailThreeDoubles)
+		s.Phase = PhasePostAction
+		return
+	}
+
+	// Handle jail
+	if p.InJail {
+		if s.Doubles {
+			p.InJail = false
+			p.JailTurns = 0
+			s.emit(LeftJail{Player: p.ID, By: JailExitDoubles})
+		} else {
+			p.JailTurns++
+			if p.JailTurns >= s.Rules.MaxJailTurns {
+				p.InJail = false
+				p.JailTurns = 0
+				by := JailExitOwingFine
+				if s.payFee(p, s.Rules.JailFine) {
+					by = JailExitForcedFine
+				}
+				s.emit(LeftJail{Player: p.ID, By: by})
+			} else {
+				s.emit(StayedInJail{Player: p.ID, Turns: p.JailTurns})
+				s.Phase = PhasePostAction
+				return
+			}
+		}
+	}
+
+	s.movePlayer(p, total)
+	s.resolveLanding()
+}
+
+// movePlayer advances a player clockwise, paying the salary if they pass GO.
+func (s *GameState) movePlayer(p *player.Player, steps int) {
+	newPos := (p.Position + steps) % config.SpaceCount
+	if newPos < p.Position {
+		s.collectSalary(p, newPos == config.GoPosition)
+	}
+	p.Position = newPos
+}
+
+// collectSalary pays the GO salary, doubled for landing exactly on GO under
+// that house rule.
+func (s *GameState) collectSalary(p *player.Player, landed bool) {
+	salary := s.Rules.GoSalary
+	if landed && s.Rules.DoubleSalary {
+		salary *= 2
+	}
+	p.Receive(salary)
+	s.emit(PassedGo{Player: p.ID, Amount: salary})
+}
+
+// resolveLanding handles what happens when a player lands on a space.
+func (s *GameState) resolveLanding() {
+	p := s.CurrentPlayer()
+	space := s.Board.Spaces[p.Position]
+	s.emit(Landed{Player: p.ID, Space: p.Position})
+
+	switch space.Type {
+	case board.SpaceGo:
+		// Already collected when passing
+		s.Phase = PhasePostAction
+
+	case board.SpaceProperty, board.SpaceRailroad, board.SpaceUtility:
+		prop := s.Board.Properties[p.Position]
+		if prop.OwnerID < 0 {
+			// Unowned — offer to buy
+			s.Phase = PhaseBuyDecision
+			s.emit(PurchaseOffered{Player: p.ID, Space: p.Position, Price: space.Price})
+		} else if prop.OwnerID != p.ID {
+			// Owned by someone else — pay rent
+			rent := s.CalculateRent(p.Position)
+			owner := s.Players[prop.OwnerID]
+			if prop.Mortgaged {
+				s.emit(RentWaived{Player: p.ID, Space: p.Position})
+			} else if owner.InJail && s.Rules.NoRentInJail {
+				s.emit(RentWaived{Player: p.ID, Space: p.Position, OwnerJailed: true})
+			} else {
+				s.payRent(p, owner, rent, p.Position)
+			}
+			s.Phase = PhasePostAction
+		} else {
+			// Own property
+			s.Phase = PhasePostAction
+		}
+
+	case board.SpaceChance:
+		card := s.Board.ChanceDeck.Draw()
+		s.emit(CardDrawn{Player: p.ID, Deck: DeckChance, Card: card})
+		s.executeCard(card)
+
+	case board.SpaceCommunityChest:
+		card := s.Board.CommunityDeck.Draw()
+		s.emit(CardDrawn{Player: p.ID, Deck: DeckCommunity, Card: card})
+		s.executeCard(card)
+
+	case board.SpaceTax:
+		if p.Position == 4 {
+			// Income Tax: player chooses between flat 200 MAD or 10% of net worth
+			s.Phase = PhaseIncomeTax
+		} else {
+			// Luxury Tax or other flat taxes
+			if s.payFee(p, space.TaxAmount) {
+				s.emit(TaxPaid{Player: p.ID, Space: p.Position, Amount: space.TaxAmount})
+			}
+			s.Phase = PhasePostAction
+		}
+
+	case board.SpaceJail:
+		s.Phase = PhasePostAction
+
+	case board.SpaceFreeParking:
+		if s.Rules.FreeParkingJackpot && s.Jackpot > 0 {
+			p.Receive(s.Jackpot)
+			s.emit(JackpotWon{Player: p.ID, Amount: s.Jackpot})
+			s.Jackpot = 0
+		}
+		s.Phase = PhasePostAction
+
+	case board.SpaceGoToJail:
+		s.sendToJail(p, JailSpace)
+		s.Phase = PhasePostAction
+	}
+}
+
+// executeCard applies a card's effect.
+func (s *GameState) executeCard(card board.Card) {
+	p := s.CurrentPlayer()
+	s.Phase = PhasePostAction
+
+	switch card.Effect {
+	case board.EffectCollect:
+		p.Receive(card.Amount)
+		s.emit(Collected{Player: p.ID, From: Bank, Amount: card.Amount})
+
+	case board.EffectPay:
+		if s.payFee(p, card.Amount) {
+			s.emit(Paid{Player: p.ID, To: s.feeCreditor(), Amount: card.Amount})
+		}
+
+	case board.EffectMoveTo:
+		target := card.Amount
+		// Check if passing GO
+		if target < p.Position || target == config.GoPosition {
+			s.collectSalary(p, target == config.GoPosition)
+		}
+		p.Position = target
+		s.resolveLanding()
+
+	case board.EffectMoveSteps:
+		p.Position = (p.Position + card.Amount + config.SpaceCount) % config.SpaceCount
+		s.resolveLanding()
+
+	case board.EffectGoToJail:
+		s.sendToJail(p, JailCard)
+
+	case board.EffectGetOutOfJail:
+		p.GetOutOfJailCards++
+		s.emit(JailCardReceived{Player: p.ID})
+
+	case board.EffectPayPerHouse:
+		totalHouses := 0
+		totalHotels := 0
+		for _, idx := range p.Properties {
+			h := s.Board.Properties[idx].Houses
+			if h == config.HotelLevel {
+				totalHotels++
+			} else {
+				totalHouses += h
+			}
+		}
+		cost := totalHouses*card.Amount + totalHotels*card.AmountHotel
+		if s.payFee(p, cost) {
+			s.emit(RepairsPaid{Player: p.ID, Amount: cost, Houses: totalHouses, Hotels: totalHotels})
+		}
+
+	case board.EffectCollectAll:
+		// Anyone short settles in seat order once the card is resolved
+		for _, other := range s.Players {
+			if other.ID != p.ID && !other.Bankrupt {
+				if s.payDebt(other, p, card.Amount) {
+					s.emit(Collected{Player: p.ID, From: other.ID, Amount: card.Amount})
+				}
+			}
+		}
+
+	case board.EffectPayAll:
+		for _, other := range s.Players {
+			if other.ID != p.ID && !other.Bankrupt {
+				if s.payDebt(p, other, card.Amount) {
+					s.emit(Paid{Player: p.ID, To: other.ID, Amount: card.Amount})
+				}
+			}
+		}
+
+	case board.EffectMoveNearest:
+		// Amount == 1: nearest railroad, Amount == 2: nearest utility
+		var targets []int
+		if card.Amount == 1 {
+			targets = s.Board.RailroadSpaces()
+		} else {
+			targets = s.Board.UtilitySpaces()
+		}
+		nearest := findNearestClockwise(p.Position, targets)
+		if nearest >= 0 {
+			// Check if passing GO
+			if nearest < p.Position {
+				s.collectSalary(p, false)
+			}
+			p.Position = nearest
+			s.resolveLanding()
+		}
+	}
+}
+
+// findNearestClockwise finds the nearest target space clockwise from the current position.
+func findNearestClockwise(from int, targets []int) int {
+	best := -1
+	bestDist := config.SpaceCount + 1
+	for _, t := range targets {
+		dist := (t - from + config.SpaceCount) % config.SpaceCount
+		if dist == 0 {
+			dist = config.SpaceCount // same space means go all the way around
+		}
+		if dist < bestDist {
+			bestDist = dist
+			best = t
+		}
+	}
+	return best
+}
+
+// buyProperty handles the player buying the current property.
+func (s *GameState) buyProperty() {
+	p := s.CurrentPlayer()
+	space := s.Board.Spaces[p.Position]
+
+	p.Pay(space.Price)
+	p.AddProperty(p.Position)
+	s.Board.Properties[p.Position].OwnerID = p.ID
+	s.emit(PropertyBought{Player: p.ID, Space: p.Position, Price: space.Price})
+	s.Phase = PhasePostAction
+}
+
+// declineBuy handles the player declining to buy — triggers an auction,
+// unless the house rules do without them.
+func (s *GameState) declineBuy() {
+	p := s.CurrentPlayer()
+	s.emit(PurchaseDeclined{Player: p.ID, Space: p.Position})
+	if s.Rules.NoAuctions {
+		s.Phase = PhasePostAction
+		return
+	}
+	s.AuctionReturnPhase = PhasePostAction
+	s.startAuction(p.Position)
+}
+
+// payJailFine releases a player from jail for the fixed fine.
+func (s *GameState) payJailFine(p *player.Player) {
+	s.payFee(p, s.Rules.JailFine)
+	p.InJail = false
+	p.JailTurns = 0
+	s.Phase = PhasePreRoll
+	s.emit(LeftJail{Player: p.ID, By: JailExitFine})
+}
+
+// useJailCard releases a player from jail with a Get Out of Jail Free card.
+func (s *GameState) useJailCard(p *player.Player) {
+	p.GetOutOfJailCards--
+	p.InJail = false
+	p.JailTurns = 0
+	s.Phase = PhasePreRoll
+	s.emit(LeftJail{Player: p.ID, By: JailExitCard})
+}
+
+// payIncomeTax charges income tax at the flat rate or 10% of net worth.
+func (s *GameState) payIncomeTax(p *player.Player, percent bool) {
+	amount := config.IncomeTax
+	if percent {
+		amount = s.PlayerNetWorth(p.ID) / 10
+	}
+	if s.payFee(p, amount) {
+		s.emit(TaxPaid{Player: p.ID, Space: p.Position, Amount: amount, Percent: percent})
+	}
+	s.Phase = PhasePostAction
+}
+
+// sendToJail moves a player to jail.
+func (s *GameState) sendToJail(p *player.Player, reason JailReason) {
+	p.Position = config.JailPosition
+	p.InJail = true
+	p.JailTurns = 0
+	s.emit(WentToJail{Player: p.ID, Reason: reason})
+}
+
+// endTurn finishes the current turn and advances to the next player.
+func (s *GameState) endTurn() {
+	// Check if doubles — roll again
+	p := s.CurrentPlayer()
+	if s.Doubles && !p.InJail && !p.Bankrupt {
+		s.Phase = PhasePreRoll
+		s.emit(RollAgain{Player: p.ID})
+		s.Die1 = 0
+		s.Die2 = 0
+		return
+	}
+
+	s.nextPlayer()
+	s.Die1 = 0
+	s.Die2 = 0
+	s.Doubles = false
+	s.StartTurn()
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/api"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+)
+
+// ServeAPI starts the local HTTP control API on addr; see package api.
+func (g *Game) ServeAPI(addr string) error {
+	s, err := api.Listen(addr)
+	if err != nil {
+		return err
+	}
+	g.API = s
+	return nil
+}
+
+// Engine returns the game on screen for the control API, or nil in the
+// menus.
+func (g *Game) Engine() *engine.GameState {
+	if g.State != StatePlaying && g.State != StateGameOver {
+		return nil
+	}
+	return g.GameState
+}
+
+// DialogName names the open dialog for the control API.
+func (g *Game) DialogName() string {
+	if g.Dialog == DialogNone {
+		return ""
+	}
+	return g.Dialog.String()
+}
+
+// Act makes a move for a human seat at this machine on behalf of the
+// control API, as if it had been clicked.
+func (g *Game) Act(id int, a engine.Action) error {
+	if g.State != StatePlaying {
+		return errors.New("no game in progress")
+	}
+	if g.animating() || len(g.netQueue) > 0 || g.awaitingHost {
+		return errors.New("the board is still moving; try again")
+	}
+	if err := g.Validate(id, a); err != nil {
+		return err
+	}
+	switch {
+	case g.Players[id].IsAI:
+		return fmt.Errorf("%s is played by the AI", g.Players[id].Name)
+	case !g.controls(id):
+		return fmt.Errorf("%s is played from another machine", g.Players[id].Name)
+	}
+	g.Viewer = id
+	if g.Client != nil {
+		g.sendAction(a)
+		return nil
+	}
+	g.apply(id, a)
+	return nil
+}
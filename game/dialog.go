@@ -0,0 +1,318 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/glow"
+)
+
+// dialogOption is one button in a dialog. Decisions carry the engine action
+// they send; purely local steps (trade toggles, cancel) carry Do instead.
+type dialogOption struct {
+	Label   string
+	Action  engine.Action // applied through perform when clicked
+	Do      func()        // UI-only effect, used when Action is nil
+	Then    func()        // runs after Action was accepted
+	Enabled bool
+}
+
+// dialogView is the content of the open dialog.
+type dialogView struct {
+	Title   string
+	Lines   []string
+	Options []dialogOption
+}
+
+// actionOption offers an engine action, enabled only if the engine would
+// accept it from the player it is waiting on.
+func (g *Game) actionOption(label string, a engine.Action) dialogOption {
+	return dialogOption{Label: label, Action: a, Enabled: g.IsLegal(g.Actor(), a)}
+}
+
+// uiOption offers a local UI step.
+func uiOption(label string, enabled bool, do func()) dialogOption {
+	return dialogOption{Label: label, Do: do, Enabled: enabled}
+}
+
+// drawDialogs renders the open dialog and records which option is hovered.
+func (g *Game) drawDialogs(canvas *glow.Canvas) {
+	v, ok := g.dialogView()
+	if !ok {
+		return
+	}
+	data := render.DialogData{Title: v.Title, Lines: v.Lines}
+	for i, o := range v.Options {
+		data.Buttons = append(data.Buttons, render.DialogButton{Label: o.Label, ID: i, Enabled: o.Enabled})
+	}
+	g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
+}
+
+// handleDialogClicks runs the hovered dialog option.
+func (g *Game) handleDialogClicks() {
+	v, ok := g.dialogView()
+	if !ok || g.DialogHovered < 0 || g.DialogHovered >= len(v.Options) {
+		return
+	}
+	o := v.Options[g.DialogHovered]
+	if !o.Enabled {
+		return
+	}
+	if o.Action == nil {
+		o.Do()
+		return
+	}
+	if g.perform(o.Action) && o.Then != nil {
+		o.Then()
+	}
+}
+
+// dialogView builds the open dialog from the current state. The option
+// index doubles as the button ID, so drawing and clicking always agree.
+func (g *Game) dialogView() (dialogView, bool) {
+	switch g.Dialog {
+	case DialogBuyProperty:
+		p := g.CurrentPlayer()
+		space := g.Board.Spaces[p.Position]
+		declineLabel := "Decline (Auction)"
+		if g.Rules.NoAuctions {
+			declineLabel = "Decline"
+		}
+		return dialogView{
+			Title: "Buy Property?",
+			Lines: []string{
+				space.Name,
+				fmt.Sprintf("Price: %d MAD", space.Price),
+				"Your money: " + g.cashText(p),
+			},
+			Options: []dialogOption{
+				g.actionOption(fmt.Sprintf("Buy for %d MAD", space.Price), engine.BuyProperty{}),
+				g.actionOption(declineLabel, engine.DeclineBuy{}),
+			},
+		}, true
+
+	case DialogIncomeTax:
+		p := g.CurrentPlayer()
+		tenPercent := g.PlayerNetWorth(p.ID) / 10
+		return dialogView{
+			Title: "Impot sur le Revenu",
+			Lines: []string{
+				fmt.Sprintf("%s must pay income tax.", p.Name),
+				fmt.Sprintf("Net worth: %d MAD", g.PlayerNetWorth(p.ID)),
+			},
+			Options: []dialogOption{
+				g.actionOption(fmt.Sprintf("Pay %d MAD (flat)", config.IncomeTax), engine.PayIncomeTax{}),
+				g.actionOption(fmt.Sprintf("Pay 10%% (%d MAD)", tenPercent), engine.PayIncomeTax{Percent: true}),
+			},
+		}, true
+
+	case DialogJailOptions:
+		p := g.CurrentPlayer()
+		return dialogView{
+			Title: "In Jail!",
+			Lines: []string{
+				fmt.Sprintf("%s is in jail (turn %d/%d)", p.Name, p.JailTurns+1, g.Rules.MaxJailTurns),
+			},
+			Options: []dialogOption{
+				g.actionOption(fmt.Sprintf("Pay %d MAD fine", g.Rules.JailFine), engine.PayJailFine{}),
+				g.actionOption("Use Get Out of Jail card", engine.UseJailCard{}),
+				g.actionOption("Try to roll doubles", engine.RollDice{}),
+			},
+		}, true
+
+	case DialogBuild:
+		opts := g.buildOptions()
+		opts = append(opts, uiOption("Cancel", true, g.closeDialog))
+		return dialogView{
+			Title:   "Build",
+			Lines:   []string{"Select a property to build on or sell from:"},
+			Options: opts,
+		}, true
+
+	case DialogMortgage:
+		opts := g.mortgageOptions()
+		opts = append(opts, uiOption("Cancel", true, g.closeDialog))
+		return dialogView{
+			Title:   "Mortgage",
+			Lines:   []string{"Select a property:"},
+			Options: opts,
+		}, true
+
+	case DialogAuction:
+		p := g.Players[g.AuctionCurrent]
+		space := g.Board.Spaces[g.AuctionSpaceIdx]
+		bid := g.AuctionHighBid + config.BidIncrement
+		return dialogView{
+			Title: "Auction",
+			Lines: []string{
+				fmt.Sprintf("Property: %s", space.Name),
+				fmt.Sprintf("Current bid: %d MAD", g.AuctionHighBid),
+				fmt.Sprintf("%s's turn to bid", p.Name),
+			},
+			Options: []dialogOption{
+				g.actionOption(fmt.Sprintf("Bid %d MAD", bid), engine.Bid{Amount: bid}),
+				g.actionOption("Pass", engine.Pass{}),
+			},
+		}, true
+
+	case DialogBankruptcy:
+		return g.debtView(), true
+
+	case DialogMortgagedTransfer:
+		t := g.Transfers[0]
+		p := g.Players[t.Player]
+		space := g.Board.Spaces[t.Space]
+		cost, interest := g.UnmortgageCost(t.Space), g.TransferInterest(t.Space)
+		return dialogView{
+			Title: "Mortgaged Property",
+			Lines: []string{
+				fmt.Sprintf("%s receives %s, which is mortgaged.", p.Name, space.Name),
+				fmt.Sprintf("Unmortgage it now, or pay %d%% interest", g.Rules.InterestRate),
+				"and unmortgage it later at the usual cost.",
+				"Cash: " + g.cashText(p),
+			},
+			Options: []dialogOption{
+				g.actionOption(fmt.Sprintf("Unmortgage (%d MAD)", cost), engine.ReceiveMortgaged{Unmortgage: true}),
+				g.actionOption(fmt.Sprintf("Keep mortgaged (%d MAD)", interest), engine.ReceiveMortgaged{}),
+			},
+		}, true
+
+	case DialogTrade:
+		return g.tradeView(), true
+
+	case DialogTradeReceived:
+		if g.PendingOffer == nil {
+			return dialogView{}, false
+		}
+		offer := g.PendingOffer
+		from := g.Players[offer.FromPlayer]
+		lines := []string{fmt.Sprintf("%s offers you:", from.Name)}
+		lines = append(lines, g.offerLines(offer.OfferedProps, offer.OfferedMoney, offer.OfferedJailCards)...)
+		lines = append(lines, "In exchange for:")
+		lines = append(lines, g.offerLines(offer.WantedProps, offer.WantedMoney, offer.WantedJailCards)...)
+		lines = append(lines, g.transferLines("You pay", offer.OfferedProps)...)
+		lines = append(lines, g.transferLines(from.Name+" pays", offer.WantedProps)...)
+		return dialogView{
+			Title: "Trade Offer Received",
+			Lines: lines,
+			Options: []dialogOption{
+				g.actionOption("Accept", engine.AcceptTrade{}),
+				g.actionOption("Decline", engine.DeclineTrade{}),
+			},
+		}, true
+	}
+	return dialogView{}, false
+}
+
+// debtView offers the ways to settle the debt being paid: pay it once the
+// cash is there, raise the cash from houses, mortgages or a trade, or give up.
+func (g *Game) debtView() dialogView {
+	debt := g.Debts[0]
+	p := g.Players[debt.Debtor]
+	canSell, canMortgage := false, false
+	for _, a := range g.LegalActions(p.ID) {
+		switch a.(type) {
+		case engine.SellHouse:
+			canSell = true
+		case engine.Mortgage:
+			canMortgage = true
+		}
+	}
+	return dialogView{
+		Title: "Debt",
+		Lines: []string{
+			fmt.Sprintf("%s owes %s %d MAD.", p.Name, g.PartyName(debt.Creditor), debt.Amount),
+			"Cash: " + g.cashText(p),
+			"Sell houses, mortgage or trade to raise the rest.",
+		},
+		Options: []dialogOption{
+			g.actionOption(fmt.Sprintf("Pay %d MAD", debt.Amount), engine.PayDebt{}),
+			uiOption("Sell houses...", canSell, g.openBuildDialog),
+			uiOption("Mortgage...", canMortgage, g.openMortgageDialog),
+			uiOption("Trade...", true, g.openTradeDialog),
+			g.actionOption("Declare bankruptcy", engine.DeclareBankruptcy{}),
+		},
+	}
+}
+
+// transferLines states what receiving the mortgaged lots among props will
+// cost, or nothing if none are mortgaged.
+func (g *Game) transferLines(who string, props []int) []string {
+	interest, unmortgage := g.TransferCost(props)
+	if unmortgage == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s %d MAD interest or %d to unmortgage", who, interest, unmortgage)}
+}
+
+// buildOptions lists a Build and a SellHouse option for every property
+// where the even-building rules allow one.
+func (g *Game) buildOptions() []dialogOption {
+	p := g.Players[g.Actor()]
+	var opts []dialogOption
+	for _, idx := range p.Properties {
+		space := g.Board.Spaces[idx]
+		prop := g.Board.Properties[idx]
+		if g.CanBuildOnSpace(idx) {
+			level := "house"
+			if prop.Houses == config.HousesPerHotel {
+				level = "HOTEL"
+			}
+			label := fmt.Sprintf("%s (%s, %d MAD)", space.Name, level, space.HouseCost)
+			opts = append(opts, g.actionOption(label, engine.Build{Space: idx}))
+		}
+	}
+	for _, idx := range p.Properties {
+		space := g.Board.Spaces[idx]
+		prop := g.Board.Properties[idx]
+		if g.CanSellHouseOnSpace(idx) {
+			level := "house"
+			if prop.Houses == config.HotelLevel {
+				level = "hotel"
+			}
+			label := fmt.Sprintf("Sell %s on %s (+%d MAD)", level, space.Name, space.HouseCost/2)
+			opts = append(opts, g.actionOption(label, engine.SellHouse{Space: idx}))
+		}
+	}
+	return opts
+}
+
+// mortgageOptions lists a Mortgage or Unmortgage option for every
+// unimproved property.
+func (g *Game) mortgageOptions() []dialogOption {
+	var opts []dialogOption
+	for _, idx := range g.Players[g.Actor()].Properties {
+		space := g.Board.Spaces[idx]
+		prop := g.Board.Properties[idx]
+		switch {
+		case prop.Mortgaged:
+			label := fmt.Sprintf("Unmortgage %s (%d MAD)", space.Name, g.UnmortgageCost(idx))
+			opts = append(opts, g.actionOption(label, engine.Unmortgage{Space: idx}))
+		case prop.Houses == 0:
+			label := fmt.Sprintf("Mortgage %s (+%d MAD)", space.Name, g.MortgageValue(idx))
+			opts = append(opts, g.actionOption(label, engine.Mortgage{Space: idx}))
+		}
+	}
+	return opts
+}
+
+// offerLines lists one side of a trade offer.
+func (g *Game) offerLines(props []int, money, jailCards int) []string {
+	var lines []string
+	for _, idx := range props {
+		name := g.Board.Spaces[idx].Name
+		if g.Board.Properties[idx].Mortgaged {
+			name += " (mortgaged)"
+		}
+		lines = append(lines, "  "+name)
+	}
+	if money > 0 {
+		lines = append(lines, fmt.Sprintf("  %d MAD", money))
+	}
+	if jailCards > 0 {
+		lines = append(lines, "  Jail Card")
+	}
+	return lines
+}
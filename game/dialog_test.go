@@ -0,0 +1,76 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/audio"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+	"github.com/AchrafSoltani/glow"
+)
+
+// newTestGame shows a game between n humans under rules, without sound or
+// save slots.
+func newTestGame(n int, rules engine.RuleSet) *Game {
+	g := &Game{
+		BoardRenderer: render.NewBoardRenderer(),
+		Audio:         &audio.Engine{},
+		Layout:        config.NewLayout(config.WindowWidth, config.WindowHeight),
+	}
+	var players []*player.Player
+	for i := 0; i < n; i++ {
+		players = append(players, player.NewPlayer(i, string(rune('A'+i)), false))
+	}
+	g.beginGame(engine.New(players, rng.New(1), rules))
+	return g
+}
+
+// loadDice makes the next roll come up d1 and d2.
+func loadDice(t *testing.T, g *Game, d1, d2 int) {
+	t.Helper()
+	r := rng.New(0)
+	for state := uint64(0); state < 10000; state++ {
+		r.SetState(state)
+		if r.Intn(6)+1 == d1 && r.Intn(6)+1 == d2 {
+			g.Rand.SetState(state)
+			return
+		}
+	}
+	t.Fatalf("no roll of %d and %d found", d1, d2)
+}
+
+func TestDebtDialog(t *testing.T) {
+	for _, tc := range []struct {
+		jackpot  bool
+		creditor string
+	}{
+		{false, "the bank"},
+		{true, "the Free Parking pot"},
+	} {
+		rules := engine.StandardRules()
+		rules.FreeParkingJackpot = tc.jackpot
+		g := newTestGame(2, rules)
+		p := g.Players[0]
+		p.AddProperty(1)
+		g.Board.Properties[1].OwnerID = 0
+		p.Money, p.Position = 10, 35
+
+		// Short of the luxury tax
+		loadDice(t, g, 1, 2)
+		if !g.apply(0, engine.RollDice{}) {
+			t.Fatal("could not roll")
+		}
+		if g.Phase != engine.PhaseDebt || g.Dialog != DialogBankruptcy {
+			t.Fatalf("jackpot %t: %s with dialog %s, want the debt dialog", tc.jackpot, g.Phase, g.Dialog)
+		}
+		g.Draw(glow.NewCanvas(config.WindowWidth, config.WindowHeight))
+		view, _ := g.dialogView()
+		if want := "owes " + tc.creditor; !strings.Contains(view.Lines[0], want) {
+			t.Errorf("jackpot %t: %q, want it to say %q", tc.jackpot, view.Lines[0], want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package game
+
+import "github.com/AchrafSoltani/MoroccanMonopoly/engine"
+
+// maxMessages is how many log lines the HUD keeps.
+const maxMessages = 12
+
+// attach makes s the running game and derives the message log, sounds and
+// statistics from its event stream.
+func (g *Game) attach(s *engine.GameState) {
+	g.GameState = s
+	g.Messages = nil
+	g.pendingEvents = nil
+	g.autosaveDue = false
+	g.Stats = &engine.Stats{}
+	s.Subscribe(g.Stats.Record)
+	s.Subscribe(g.onEvent)
+}
+
+// onEvent logs an event straight away and queues it for its sound, which
+// waits until the dice and token have settled. A host also keeps it to
+// relay with the action that led to it.
+func (g *Game) onEvent(e engine.Event) {
+	if g.Host != nil {
+		g.applied = append(g.applied, e)
+	}
+	if msg := g.Describe(e); msg != "" {
+		g.AddMessage(msg)
+	}
+	g.pendingEvents = append(g.pendingEvents, e)
+	if _, ok := e.(engine.TurnStarted); ok {
+		g.autosaveDue = true
+	}
+}
+
+// AddMessage appends a message to the log.
+func (g *Game) AddMessage(msg string) {
+	g.Messages = append(g.Messages, msg)
+	if len(g.Messages) > maxMessages {
+		g.Messages = g.Messages[len(g.Messages)-maxMessages:]
+	}
+}
+
+// playEvents plays the sounds for events queued since the last call.
+func (g *Game) playEvents() {
+	for _, e := range g.pendingEvents {
+		switch e.(type) {
+		case engine.PropertyBought, engine.AuctionWon:
+			g.Audio.PlayPurchase()
+		case engine.RentPaid:
+			g.Audio.PlayRent()
+		case engine.CardDrawn:
+			g.Audio.PlayCardDraw()
+		case engine.WentToJail:
+			g.Audio.PlayJail()
+		case engine.PassedGo:
+			g.Audio.PlayPassGo()
+		case engine.Bankrupt:
+			g.Audio.PlayBankruptcy()
+		}
+	}
+	g.pendingEvents = nil
+}
@@ -4,46 +4,81 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/AchrafSoltani/MoroccanMonopoly/api"
 	"github.com/AchrafSoltani/MoroccanMonopoly/audio"
 	"github.com/AchrafSoltani/MoroccanMonopoly/board"
 	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/lan"
+	"github.com/AchrafSoltani/MoroccanMonopoly/markov"
 	"github.com/AchrafSoltani/MoroccanMonopoly/player"
 	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
 	"github.com/AchrafSoltani/MoroccanMonopoly/save"
 	"github.com/AchrafSoltani/glow"
 )
 
 // Game holds all game state and coordinates updates and rendering.
+// The rules live in the embedded engine state; Game adds presentation on top.
 type Game struct {
+	*engine.GameState
+
 	State     GameState
-	Phase     TurnPhase
 	Dialog    DialogType
-	Board     *board.Board
-	Players   []*player.Player
-	Current   int // index of current player
 	GameTimer float64
 	Layout    config.Layout
-
-	// Dice
-	Die1, Die2    int
-	Doubles       bool
-	DoublesCount  int
+	Seed      int64  // seed for new games; 0 picks a fresh one each game
+	Notice    string // shown on the menu, e.g. why a save would not load
+
+	// Players for new games, from the setup screen
+	Seats          [config.MaxPlayers]Seat
+	SeatCount      int
+	ShuffleSeats   bool // random turn order
+	SetupCursor    int
+	SetupNaming    bool
+	SetupNameInput string
+
+	// Hot-seat privacy
+	HandOff    bool // cover the screen until the next human is ready
+	HiddenCash bool // the HUD shows only the viewer's cash
+	Viewer     int  // the human the screen was last handed to; -1 for nobody
+
+	Heatmap HeatmapMode // board overlay, switched with O
+
+	// LAN play: at most one of Host and Client is set
+	Host         *lan.Host
+	Client       *lan.Client
+	netQueue     []lan.Inbound  // actions received, waiting for the board
+	awaitingHost bool           // a client action is on its way to the host
+	reconnecting bool           // a client lost the host and is dialling it again
+	applied      []engine.Event // what the action being applied led to, for relaying
+
+	API *api.Server // local control API, if serving
+
+	// House rules for new games
+	HouseRules  engine.RuleSet
+	RulesCursor int
+
+	// Dice animation
 	DiceAnimTimer float64
 	DiceRolling   bool
 
 	// Token movement animation
+	MovePlayer  int
 	MoveFrom    int
-	MoveTo      int
 	MoveTimer   float64
 	MoveSteps   int
 	MoveCurrent int
 
 	// AI pacing
-	AITimer float64
+	AITimer  float64
+	thinking chan thought // an AI decision being worked out, if any
+	moves    int          // actions applied, to spot a stale decision
 
-	// Message log
-	Messages    []string
-	MaxMessages int
+	// Derived from the engine's event stream
+	Messages      []string
+	Stats         *engine.Stats
+	pendingEvents []engine.Event // awaiting their sound effect
 
 	// Renderers
 	BoardRenderer *render.BoardRenderer
@@ -54,20 +89,17 @@ type Game struct {
 	MouseClicked   bool
 	DialogHovered  int // button ID hovered in dialog (-1 = none)
 
-	// Build/mortgage selection
-	SelectableSpaces []int // space indices player can choose from
-	SelectedSpace    int   // currently selected space index (-1 = none)
-
-	// Auction state
-	AuctionSpaceIdx   int
-	AuctionBids       [config.MaxPlayers]int
-	AuctionActive     [config.MaxPlayers]bool
-	AuctionCurrent    int
-	AuctionHighBid    int
-	AuctionHighBidder int
-
-	// Trade state
-	TradePartner      int // target player index
+	// Save slots
+	SlotID        string // slot F5 saves to; empty until the first save
+	SlotName      string
+	Slots         []save.Slot
+	SlotCursor    int
+	SlotRenaming  bool
+	SlotNameInput string
+	autosaveDue   bool // a turn has started since the last autosave
+
+	// Trade builder state
+	TradePartner       int // target player index
 	TradeOfferedProps  []int
 	TradeWantedProps   []int
 	TradeOfferedMoney  int
@@ -75,22 +107,25 @@ type Game struct {
 	TradeStage         TradeState
 	TradeOfferJailCard bool // offering a jail card
 	TradeWantJailCard  bool // requesting a jail card
-	PendingOffer       *TradeOffer // for human-to-human confirmation
 
 	// Buttons
 	Buttons []render.Button
 }
 
-// NewGame creates a new game with default state.
-func NewGame() *Game {
+// NewGame creates a new game with default state. A non-zero seed makes
+// every game started from the menu replay the same dice and cards.
+func NewGame(seed int64) *Game {
 	g := &Game{
 		State:         StateMenu,
-		Board:         board.NewBoard(),
+		Seed:          seed,
 		BoardRenderer: render.NewBoardRenderer(),
 		Audio:         audio.NewEngine(),
-		MaxMessages:   12,
 		Layout:        config.NewLayout(config.WindowWidth, config.WindowHeight),
+		HouseRules:    engine.StandardRules(),
+		Seats:         defaultSeats(),
+		SeatCount:     config.MinPlayers,
 	}
+	g.refreshSlots()
 	return g
 }
 
@@ -145,12 +180,15 @@ func (g *Game) repositionButtons() {
 // Update advances the game state by dt seconds.
 func (g *Game) Update(dt float64) {
 	g.GameTimer += dt
+	g.updateLAN()
+	if g.API != nil {
+		g.API.Poll(g)
+	}
 
 	switch g.State {
 	case StateMenu:
 		g.updateMenu(dt)
-	case StateSetup:
-		g.updateSetup(dt)
+	case StateSetup, StateLoad:
 	case StatePlaying:
 		g.updatePlaying(dt)
 	case StateGameOver:
@@ -167,6 +205,12 @@ func (g *Game) Draw(canvas *glow.Canvas) {
 		g.drawMenu(canvas)
 	case StateSetup:
 		g.drawSetup(canvas)
+	case StateLoad:
+		g.drawLoad(canvas)
+	case StateRules:
+		g.drawRules(canvas)
+	case StateLobby:
+		g.drawLobby(canvas)
 	case StatePlaying:
 		g.drawPlaying(canvas)
 	case StateGameOver:
@@ -196,86 +240,84 @@ func (g *Game) KeyDown(key glow.Key) {
 		g.keyMenu(key)
 	case StateSetup:
 		g.keySetup(key)
+	case StateLoad:
+		g.keyLoad(key)
+	case StateRules:
+		g.keyRules(key)
+	case StateLobby:
+		g.keyLobby(key)
 	case StatePlaying:
 		g.keyPlaying(key)
 	case StateGameOver:
 		if key == glow.KeyEnter {
-			save.DeleteSave()
-			g.State = StateMenu
+			// A finished game has nothing left to resume
+			if g.SlotID != "" {
+				save.DeleteSlot(g.SlotID)
+			}
+			if g.Client != nil {
+				g.State = StateLobby // the host may start another
+			} else {
+				g.toMenu()
+			}
 		}
 	}
 }
 
-// AddMessage appends a message to the log.
-func (g *Game) AddMessage(msg string) {
-	g.Messages = append(g.Messages, msg)
-	if len(g.Messages) > g.MaxMessages {
-		g.Messages = g.Messages[len(g.Messages)-g.MaxMessages:]
+// StartGame initialises a new game with the given players, seated in the
+// order given unless shuffle asks for a random turn order.
+func (g *Game) StartGame(players []*player.Player, shuffle bool) {
+	seed := g.Seed
+	if seed == 0 {
+		seed = rng.NewSeed()
+	}
+	r := rng.New(seed)
+	if shuffle {
+		// Drawn from the game's own source, so a fixed seed replays the order too
+		r.Shuffle(len(players), func(i, j int) { players[i], players[j] = players[j], players[i] })
+		for i, p := range players {
+			p.ID = i
+		}
 	}
+	g.beginGame(engine.New(players, r, g.HouseRules))
 }
 
-// StartGame initialises a new game with the given players.
-func (g *Game) StartGame(players []*player.Player) {
-	g.Players = players
-	g.Current = 0
+// beginGame shows a game from its first turn: one started here, or one a
+// LAN host started.
+func (g *Game) beginGame(s *engine.GameState) {
+	g.attach(s)
 	g.State = StatePlaying
-	g.Phase = PhasePreRoll
-	g.Board = board.NewBoard()
-	g.Messages = nil
+	g.Viewer = -1
+	g.Notice = ""
+	g.SlotID, g.SlotName = "", ""
+	g.Dialog = DialogNone
+	g.netQueue = nil
+	g.awaitingHost = false
+	g.resetAnimation()
 	g.AddMessage("Game started! Roll the dice.")
 
 	// Set up buttons
 	g.setupButtons()
+	g.syncDialog()
+}
+
+// toMenu returns to the main menu with the save list up to date.
+func (g *Game) toMenu() {
+	g.refreshSlots()
+	g.State = StateMenu
 }
 
 func (g *Game) setupButtons() {
 	// Create buttons with placeholder positions; repositionButtons() will set the real coords.
-	g.Buttons = []render.Button{
-		render.NewButton("Roll Dice", 0, 0, 0, 0),
-		render.NewButton("Buy", 0, 0, 0, 0),
-		render.NewButton("Auction", 0, 0, 0, 0),
-		render.NewButton("Build", 0, 0, 0, 0),
-		render.NewButton("Mortgage", 0, 0, 0, 0),
-		render.NewButton("Trade", 0, 0, 0, 0),
-		render.NewButton("End Turn", 0, 0, 0, 0),
+	g.Buttons = nil
+	for _, pb := range g.panelButtons() {
+		g.Buttons = append(g.Buttons, render.NewButton(pb.Label, 0, 0, 0, 0))
 	}
 	g.repositionButtons()
 }
 
-// currentPlayer returns the active player.
-func (g *Game) currentPlayer() *player.Player {
-	if len(g.Players) == 0 {
-		return nil
-	}
-	return g.Players[g.Current]
-}
-
-// alivePlayers returns all non-bankrupt players.
-func (g *Game) alivePlayers() []*player.Player {
-	var alive []*player.Player
-	for _, p := range g.Players {
-		if !p.Bankrupt {
-			alive = append(alive, p)
-		}
-	}
-	return alive
-}
-
-// nextPlayer advances to the next non-bankrupt player.
-func (g *Game) nextPlayer() {
-	for {
-		g.Current = (g.Current + 1) % len(g.Players)
-		if !g.Players[g.Current].Bankrupt {
-			break
-		}
-	}
-	g.DoublesCount = 0
-}
-
 // Stub methods for states not yet implemented
 
 func (g *Game) updateMenu(dt float64)    {}
-func (g *Game) updateSetup(dt float64)   {}
 func (g *Game) updateGameOver(dt float64) {
 	if g.MouseClicked {
 		// Return to menu on any click
@@ -298,17 +340,24 @@ func (g *Game) drawMenu(canvas *glow.Canvas) {
 	canvas.DrawLine(cx-120, 240, cx+120, 240, render.ZelligeGold)
 
 	y := 280
-	render.DrawTextCentered(canvas, "ENTER - New Game (1 Human + 1 AI)", cx, y, render.TextLight, 1)
-	y += 20
-	render.DrawTextCentered(canvas, "2 - Two Players (1H + 1AI)", cx, y, render.TextLight, 1)
-	y += 16
-	render.DrawTextCentered(canvas, "3 - Three Players (1H + 2AI)", cx, y, render.TextLight, 1)
-	y += 16
-	render.DrawTextCentered(canvas, "4 - Four Players (1H + 3AI)", cx, y, render.TextLight, 1)
+	render.DrawTextCentered(canvas, "ENTER - New Game", cx, y, render.TextLight, 1)
+	y += 24
+	rules := "H - House Rules (standard)"
+	if !g.HouseRules.IsStandard() {
+		rules = "H - House Rules (custom)"
+	}
+	render.DrawTextCentered(canvas, rules, cx, y, render.ZelligeGold, 1)
 
-	if save.HasSave() {
+	if len(g.Slots) > 0 {
 		y += 30
-		render.DrawTextCentered(canvas, "R - Resume Saved Game", cx, y, render.TextGold, 2)
+		render.DrawTextCentered(canvas, "R - Resume "+g.Slots[0].Meta.Name, cx, y, render.TextGold, 2)
+		y += 24
+		render.DrawTextCentered(canvas, fmt.Sprintf("L - Load Game (%d saves)", len(g.Slots)), cx, y, render.TextGold, 1)
+	}
+
+	if g.Notice != "" {
+		y += 24
+		render.DrawTextCentered(canvas, g.Notice, cx, y, render.ColorRed, 1)
 	}
 
 	y += 50
@@ -319,24 +368,28 @@ func (g *Game) drawMenu(canvas *glow.Canvas) {
 	render.DrawTextCentered(canvas, "Currency: MAD (Moroccan Dirham)", cx, y, render.ZelligeGold, 1)
 }
 
-func (g *Game) drawSetup(canvas *glow.Canvas) {}
-
 func (g *Game) drawPlaying(canvas *glow.Canvas) {
+	if g.handOffDue() {
+		g.drawHandOff(canvas)
+		return
+	}
+
 	// Draw board
 	g.BoardRenderer.Draw(canvas, g.Board)
-	g.BoardRenderer.DrawOwnershipDots(canvas, g.Board)
+	g.drawHeatmap(canvas)
+	g.BoardRenderer.DrawOwnershipDots(canvas, g.Board, g.Players)
 
 	// Draw tokens
 	for _, p := range g.Players {
 		if !p.Bankrupt {
-			render.DrawToken(canvas, p, g.BoardRenderer.SpaceRects[p.Position])
+			render.DrawToken(canvas, p, g.BoardRenderer.SpaceRects[g.tokenPosition(p)])
 		}
 	}
 
 	// Highlight current player's token
-	cp := g.currentPlayer()
+	cp := g.CurrentPlayer()
 	if cp != nil && !cp.Bankrupt {
-		render.DrawTokenHighlight(canvas, cp, g.BoardRenderer.SpaceRects[cp.Position], g.GameTimer)
+		render.DrawTokenHighlight(canvas, cp, g.BoardRenderer.SpaceRects[g.tokenPosition(cp)], g.GameTimer)
 	}
 
 	// Draw HUD panel
@@ -358,8 +411,12 @@ func (g *Game) drawPlaying(canvas *glow.Canvas) {
 	// Board space hover highlighting
 	g.drawBoardHover(canvas)
 
-	// Draw dialogs
-	g.drawDialogs(canvas)
+	// Draw dialogs once the dice and token have settled; another machine's
+	// decisions stay off this screen apart from auctions
+	g.DialogHovered = render.DialogNoHover
+	if !g.animating() && (!g.remoteActor() || g.Dialog == DialogAuction) {
+		g.drawDialogs(canvas)
+	}
 }
 
 func (g *Game) drawBoardHover(canvas *glow.Canvas) {
@@ -392,14 +449,14 @@ func (g *Game) drawBoardHover(canvas *glow.Canvas) {
 				case board.SpaceRailroad:
 					ownedCount := 0
 					if prop.OwnerID >= 0 {
-						ownedCount = g.countOwnedRailroads(prop.OwnerID)
+						ownedCount = g.CountOwnedRailroads(prop.OwnerID)
 					}
 					render.DrawRailroadCard(canvas, cardX, g.Layout.WinH-150, cardW,
 						space.Name, space.Price, ownerName, ownedCount, prop.Mortgaged)
 				case board.SpaceUtility:
 					ownedCount := 0
 					if prop.OwnerID >= 0 {
-						ownedCount = g.countOwnedUtilities(prop.OwnerID)
+						ownedCount = g.CountOwnedUtilities(prop.OwnerID)
 					}
 					render.DrawUtilityCard(canvas, cardX, g.Layout.WinH-130, cardW,
 						space.Name, space.Price, ownerName, ownedCount, prop.Mortgaged)
@@ -410,284 +467,14 @@ func (g *Game) drawBoardHover(canvas *glow.Canvas) {
 						groupCol, ownerName, prop.Houses, prop.Mortgaged)
 				}
 			}
-			break
-		}
-	}
-}
-
-func (g *Game) drawDialogs(canvas *glow.Canvas) {
-	switch g.Dialog {
-	case DialogBuyProperty:
-		p := g.currentPlayer()
-		space := g.Board.Spaces[p.Position]
-		data := render.DialogData{
-			Title: "Buy Property?",
-			Lines: []string{
-				space.Name,
-				fmt.Sprintf("Price: %d MAD", space.Price),
-				fmt.Sprintf("Your money: %d MAD", p.Money),
-			},
-			Buttons: []render.DialogButton{
-				{Label: fmt.Sprintf("Buy for %d MAD", space.Price), ID: 0, Enabled: p.Money >= space.Price},
-				{Label: "Decline (Auction)", ID: 1, Enabled: true},
-			},
-		}
-		g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-	case DialogIncomeTax:
-		p := g.currentPlayer()
-		tenPercent := g.PlayerNetWorth(p.ID) / 10
-		data := render.DialogData{
-			Title: "Impot sur le Revenu",
-			Lines: []string{
-				fmt.Sprintf("%s must pay income tax.", p.Name),
-				fmt.Sprintf("Net worth: %d MAD", g.PlayerNetWorth(p.ID)),
-			},
-			Buttons: []render.DialogButton{
-				{Label: "Pay 200 MAD (flat)", ID: 0, Enabled: true},
-				{Label: fmt.Sprintf("Pay 10%% (%d MAD)", tenPercent), ID: 1, Enabled: true},
-			},
-		}
-		g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-	case DialogJailOptions:
-		p := g.currentPlayer()
-		data := render.DialogData{
-			Title: "In Jail!",
-			Lines: []string{
-				fmt.Sprintf("%s is in jail (turn %d/%d)", p.Name, p.JailTurns+1, config.MaxJailTurns),
-			},
-			Buttons: []render.DialogButton{
-				{Label: fmt.Sprintf("Pay %d MAD fine", config.JailFine), ID: 0, Enabled: p.Money >= config.JailFine},
-				{Label: "Use Get Out of Jail card", ID: 1, Enabled: p.GetOutOfJailCards > 0},
-				{Label: "Try to roll doubles", ID: 2, Enabled: true},
-			},
-		}
-		g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-	case DialogBuild:
-		p := g.currentPlayer()
-		var btns []render.DialogButton
-		for i, idx := range g.SelectableSpaces {
-			space := g.Board.Spaces[idx]
-			prop := g.Board.Properties[idx]
-			level := "house"
-			if prop.Houses == config.HousesPerHotel {
-				level = "HOTEL"
-			}
-			label := fmt.Sprintf("%s (%s, %d MAD)", space.Name, level, space.HouseCost)
-			btns = append(btns, render.DialogButton{Label: label, ID: i, Enabled: p.Money >= space.HouseCost})
-		}
-		btns = append(btns, render.DialogButton{Label: "Cancel", ID: -1, Enabled: true})
-
-		data := render.DialogData{
-			Title:   "Build",
-			Lines:   []string{"Select a property to build on:"},
-			Buttons: btns,
-		}
-		g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-	case DialogMortgage:
-		var btns []render.DialogButton
-		for i, idx := range g.SelectableSpaces {
-			space := g.Board.Spaces[idx]
-			prop := g.Board.Properties[idx]
-			if prop.Mortgaged {
-				cost := g.UnmortgageCost(idx)
-				label := fmt.Sprintf("Unmortgage %s (%d MAD)", space.Name, cost)
-				btns = append(btns, render.DialogButton{Label: label, ID: i, Enabled: true})
-			} else {
-				val := g.MortgageValue(idx)
-				label := fmt.Sprintf("Mortgage %s (+%d MAD)", space.Name, val)
-				btns = append(btns, render.DialogButton{Label: label, ID: i, Enabled: true})
-			}
-		}
-		btns = append(btns, render.DialogButton{Label: "Cancel", ID: -1, Enabled: true})
-
-		data := render.DialogData{
-			Title:   "Mortgage",
-			Lines:   []string{"Select a property:"},
-			Buttons: btns,
-		}
-		g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-	case DialogAuction:
-		p := g.Players[g.AuctionCurrent]
-		space := g.Board.Spaces[g.AuctionSpaceIdx]
-		data := render.DialogData{
-			Title: "Auction",
-			Lines: []string{
-				fmt.Sprintf("Property: %s", space.Name),
-				fmt.Sprintf("Current bid: %d MAD", g.AuctionHighBid),
-				fmt.Sprintf("%s's turn to bid", p.Name),
-			},
-			Buttons: []render.DialogButton{
-				{Label: fmt.Sprintf("Bid %d MAD", g.AuctionHighBid+10), ID: 0, Enabled: p.Money > g.AuctionHighBid+10},
-				{Label: "Pass", ID: 1, Enabled: true},
-			},
-		}
-		g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-	case DialogTrade:
-		p := g.currentPlayer()
-		switch g.TradeStage {
-		case TradeSelectPartner:
-			var btns []render.DialogButton
-			for _, other := range g.Players {
-				if other.ID != p.ID && !other.Bankrupt {
-					label := fmt.Sprintf("%s (%d MAD, %d props)", other.Name, other.Money, len(other.Properties))
-					btns = append(btns, render.DialogButton{Label: label, ID: other.ID, Enabled: true})
-				}
-			}
-			btns = append(btns, render.DialogButton{Label: "Cancel", ID: -1, Enabled: true})
-			data := render.DialogData{
-				Title:   "Trade - Select Partner",
-				Lines:   []string{"Who do you want to trade with?"},
-				Buttons: btns,
-			}
-			g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-		case TradeSelectOffer:
-			partner := g.Players[g.TradePartner]
-			var btns []render.DialogButton
-
-			// Own properties to offer (toggle)
-			for _, idx := range p.Properties {
-				space := g.Board.Spaces[idx]
-				offered := g.tradePropsContains(g.TradeOfferedProps, idx)
-				prefix := "[ ] Offer: "
-				if offered {
-					prefix = "[X] Offer: "
-				}
-				btns = append(btns, render.DialogButton{
-					Label:   prefix + space.Name,
-					ID:      idx,
-					Enabled: !g.Board.Properties[idx].Mortgaged && g.Board.Properties[idx].Houses == 0,
-				})
-			}
-			// Partner properties to request (toggle)
-			for _, idx := range partner.Properties {
-				space := g.Board.Spaces[idx]
-				wanted := g.tradePropsContains(g.TradeWantedProps, idx)
-				prefix := "[ ] Want:  "
-				if wanted {
-					prefix = "[X] Want:  "
-				}
-				btns = append(btns, render.DialogButton{
-					Label:   prefix + space.Name,
-					ID:      1000 + idx,
-					Enabled: !g.Board.Properties[idx].Mortgaged && g.Board.Properties[idx].Houses == 0,
-				})
-			}
-
-			// Money buttons
-			btns = append(btns, render.DialogButton{Label: fmt.Sprintf("Offer +50 MAD (now: %d)", g.TradeOfferedMoney), ID: 2000, Enabled: true})
-			btns = append(btns, render.DialogButton{Label: fmt.Sprintf("Offer -50 MAD (now: %d)", g.TradeOfferedMoney), ID: 2001, Enabled: g.TradeOfferedMoney >= 50})
-			btns = append(btns, render.DialogButton{Label: fmt.Sprintf("Want +50 MAD (now: %d)", g.TradeWantedMoney), ID: 2002, Enabled: true})
-			btns = append(btns, render.DialogButton{Label: fmt.Sprintf("Want -50 MAD (now: %d)", g.TradeWantedMoney), ID: 2003, Enabled: g.TradeWantedMoney >= 50})
-
-			// Jail card toggles
-			if p.GetOutOfJailCards > 0 {
-				jailLabel := "[ ] Offer Jail Card"
-				if g.TradeOfferJailCard {
-					jailLabel = "[X] Offer Jail Card"
-				}
-				btns = append(btns, render.DialogButton{Label: jailLabel, ID: 2004, Enabled: true})
-			}
-			if partner.GetOutOfJailCards > 0 {
-				jailLabel := "[ ] Want Jail Card"
-				if g.TradeWantJailCard {
-					jailLabel = "[X] Want Jail Card"
-				}
-				btns = append(btns, render.DialogButton{Label: jailLabel, ID: 2005, Enabled: true})
-			}
 
-			// Propose / Cancel
-			hasContent := len(g.TradeOfferedProps) > 0 || len(g.TradeWantedProps) > 0 ||
-				g.TradeOfferedMoney > 0 || g.TradeWantedMoney > 0 ||
-				g.TradeOfferJailCard || g.TradeWantJailCard
-			btns = append(btns, render.DialogButton{Label: "Propose Trade >>", ID: 3000, Enabled: hasContent})
-			btns = append(btns, render.DialogButton{Label: "Cancel", ID: -1, Enabled: true})
-
-			lines := []string{fmt.Sprintf("Building offer with %s:", partner.Name)}
-			data := render.DialogData{
-				Title:   "Trade - Build Offer",
-				Lines:   lines,
-				Buttons: btns,
-			}
-			g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-
-		case TradeConfirm:
-			partner := g.Players[g.TradePartner]
-			var lines []string
-			lines = append(lines, fmt.Sprintf("Trade with %s:", partner.Name))
-			lines = append(lines, "--- You give ---")
-			for _, idx := range g.TradeOfferedProps {
-				lines = append(lines, "  "+g.Board.Spaces[idx].Name)
-			}
-			if g.TradeOfferedMoney > 0 {
-				lines = append(lines, fmt.Sprintf("  %d MAD", g.TradeOfferedMoney))
-			}
-			if g.TradeOfferJailCard {
-				lines = append(lines, "  Jail Card")
-			}
-			lines = append(lines, "--- You get ---")
-			for _, idx := range g.TradeWantedProps {
-				lines = append(lines, "  "+g.Board.Spaces[idx].Name)
-			}
-			if g.TradeWantedMoney > 0 {
-				lines = append(lines, fmt.Sprintf("  %d MAD", g.TradeWantedMoney))
-			}
-			if g.TradeWantJailCard {
-				lines = append(lines, "  Jail Card")
+			// How often the space is landed on, from the board's odds
+			if space.Type != board.SpaceGoToJail {
+				odds := markov.For(g.Board)
+				render.DrawText(canvas, fmt.Sprintf("Landed on %.1f%% of turns", odds.PerTurn[i]*100),
+					g.Layout.PanelX+20, g.Layout.WinH-196, render.TextLight, 1)
 			}
-
-			data := render.DialogData{
-				Title: "Confirm Trade",
-				Lines: lines,
-				Buttons: []render.DialogButton{
-					{Label: "Propose Trade", ID: 0, Enabled: true},
-					{Label: "Go Back", ID: 1, Enabled: true},
-					{Label: "Cancel", ID: -1, Enabled: true},
-				},
-			}
-			g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
-		}
-
-	case DialogTradeReceived:
-		// Show incoming trade offer for human-to-human trades
-		if g.PendingOffer != nil {
-			from := g.Players[g.PendingOffer.FromPlayer]
-			var lines []string
-			lines = append(lines, fmt.Sprintf("%s offers you:", from.Name))
-			for _, idx := range g.PendingOffer.OfferedProps {
-				lines = append(lines, "  "+g.Board.Spaces[idx].Name)
-			}
-			if g.PendingOffer.OfferedMoney > 0 {
-				lines = append(lines, fmt.Sprintf("  %d MAD", g.PendingOffer.OfferedMoney))
-			}
-			if g.PendingOffer.OfferedJailCards > 0 {
-				lines = append(lines, "  Jail Card")
-			}
-			lines = append(lines, "In exchange for:")
-			for _, idx := range g.PendingOffer.WantedProps {
-				lines = append(lines, "  "+g.Board.Spaces[idx].Name)
-			}
-			if g.PendingOffer.WantedMoney > 0 {
-				lines = append(lines, fmt.Sprintf("  %d MAD", g.PendingOffer.WantedMoney))
-			}
-			if g.PendingOffer.WantedJailCards > 0 {
-				lines = append(lines, "  Jail Card")
-			}
-			data := render.DialogData{
-				Title: "Trade Offer Received",
-				Lines: lines,
-				Buttons: []render.DialogButton{
-					{Label: "Accept", ID: 0, Enabled: true},
-					{Label: "Decline", ID: 1, Enabled: true},
-				},
-			}
-			g.DialogHovered = render.DrawDialog(canvas, data, g.MouseX, g.MouseY)
+			break
 		}
 	}
 }
@@ -703,10 +490,10 @@ func (g *Game) drawGameOver(canvas *glow.Canvas) {
 	render.DrawTextCentered(canvas, "GAME OVER", cx, 100, render.TextGold, 4)
 
 	// Winner announcement
-	alive := g.alivePlayers()
+	alive := g.AlivePlayers()
 	if len(alive) == 1 {
 		winner := alive[0]
-		render.DrawTextCentered(canvas, winner.Name+" WINS!", cx, 160, render.PlayerColors[winner.ID%4], 3)
+		render.DrawTextCentered(canvas, winner.Name+" WINS!", cx, 160, render.PlayerColors[winner.Color], 3)
 	}
 
 	// Build rankings sorted by net worth (descending)
@@ -747,20 +534,21 @@ func (g *Game) drawGameOver(canvas *glow.Canvas) {
 	y += 20
 
 	// Header
-	hx := cx - 180
+	hx := cx - 210
 	render.DrawText(canvas, "#", hx, y, render.TextLight, 1)
 	render.DrawText(canvas, "Player", hx+20, y, render.TextLight, 1)
 	render.DrawText(canvas, "Cash", hx+160, y, render.TextLight, 1)
 	render.DrawText(canvas, "Worth", hx+230, y, render.TextLight, 1)
 	render.DrawText(canvas, "Props", hx+300, y, render.TextLight, 1)
 	render.DrawText(canvas, "H/Ht", hx+350, y, render.TextLight, 1)
+	render.DrawText(canvas, "Rent", hx+400, y, render.TextLight, 1)
 	y += 16
 
 	// Separator
-	canvas.DrawLine(hx, y-2, hx+380, y-2, render.PanelBorder)
+	canvas.DrawLine(hx, y-2, hx+440, y-2, render.PanelBorder)
 
 	for rank, s := range stats {
-		col := render.PlayerColors[s.p.ID%4]
+		col := render.PlayerColors[s.p.Color]
 		status := ""
 		if s.p.Bankrupt {
 			col = render.MortgageColor
@@ -772,6 +560,7 @@ func (g *Game) drawGameOver(canvas *glow.Canvas) {
 		render.DrawText(canvas, fmt.Sprintf("%d", s.netWorth), hx+230, y, col, 1)
 		render.DrawText(canvas, fmt.Sprintf("%d", len(s.p.Properties)), hx+300, y, col, 1)
 		render.DrawText(canvas, fmt.Sprintf("%d/%d", s.houses, s.hotels), hx+350, y, col, 1)
+		render.DrawText(canvas, fmt.Sprintf("%d", g.Stats.RentReceived[s.p.ID]), hx+400, y, col, 1)
 		y += 16
 	}
 
@@ -782,138 +571,65 @@ func (g *Game) drawGameOver(canvas *glow.Canvas) {
 func (g *Game) keyMenu(key glow.Key) {
 	switch key {
 	case glow.KeyEnter:
-		// Default: 1 human + 1 AI
-		players := []*player.Player{
-			player.NewPlayer(0, "Player 1", false),
-			player.NewPlayer(1, "AI Player", true),
-		}
-		g.StartGame(players)
+		g.openSetupScreen()
 	case glow.KeyR:
-		if save.HasSave() {
-			g.loadGame()
-		}
-	case glow.Key2:
-		players := []*player.Player{
-			player.NewPlayer(0, "Player 1", false),
-			player.NewPlayer(1, "AI Player", true),
-		}
-		g.StartGame(players)
-	case glow.Key3:
-		players := []*player.Player{
-			player.NewPlayer(0, "Player 1", false),
-			player.NewPlayer(1, "AI Player 1", true),
-			player.NewPlayer(2, "AI Player 2", true),
+		// Slots are listed newest first
+		if len(g.Slots) > 0 {
+			g.loadGame(g.Slots[0].ID)
 		}
-		g.StartGame(players)
-	case glow.Key4:
-		players := []*player.Player{
-			player.NewPlayer(0, "Player 1", false),
-			player.NewPlayer(1, "AI Player 1", true),
-			player.NewPlayer(2, "AI Player 2", true),
-			player.NewPlayer(3, "AI Player 3", true),
-		}
-		g.StartGame(players)
+	case glow.KeyL:
+		g.openLoadScreen()
+	case glow.KeyH:
+		g.openRulesScreen()
 	}
 }
 
-func (g *Game) keySetup(key glow.Key) {}
-
 func (g *Game) keyPlaying(key glow.Key) {
-	if key == glow.KeyF5 {
+	switch key {
+	case glow.KeyF5:
+		if g.Client != nil {
+			g.AddMessage("Only the host can save the game")
+			return
+		}
 		g.saveGame()
+	case glow.KeyO:
+		g.cycleHeatmap()
+	case glow.KeyA:
+		if g.Host != nil {
+			g.releaseDropped()
+		}
+	case glow.KeyEscape:
+		if g.reconnecting {
+			g.leaveLAN("")
+		}
 	}
 }
 
-// saveGame serialises current game state to disk.
-func (g *Game) saveGame() {
-	data := &save.SaveData{
-		Current:    g.Current,
-		Properties: save.BoardToPropertyData(g.Board),
-		HousePool:  g.Board.HousePool,
-		HotelPool:  g.Board.HotelPool,
-		Die1:       g.Die1,
-		Die2:       g.Die2,
-		Messages:   g.Messages,
-	}
-
-	for _, p := range g.Players {
-		data.Players = append(data.Players, save.PlayerData{
-			ID:                p.ID,
-			Name:              p.Name,
-			IsAI:              p.IsAI,
-			Money:             p.Money,
-			Position:          p.Position,
-			InJail:            p.InJail,
-			JailTurns:         p.JailTurns,
-			Bankrupt:          p.Bankrupt,
-			Properties:        p.Properties,
-			GetOutOfJailCards: p.GetOutOfJailCards,
-		})
-	}
-
-	if err := save.Save(data); err != nil {
-		g.AddMessage("Save failed: " + err.Error())
-	} else {
-		g.AddMessage("Game saved!")
-	}
-}
-
-// loadGame restores game state from disk.
-func (g *Game) loadGame() bool {
-	data, err := save.Load()
-	if err != nil {
-		g.AddMessage("Load failed: " + err.Error())
-		return false
-	}
-
-	g.Board = board.NewBoard()
-	save.PropertyDataToBoard(g.Board, data.Properties)
-	g.Board.HousePool = data.HousePool
-	g.Board.HotelPool = data.HotelPool
-
-	g.Players = nil
-	for _, pd := range data.Players {
-		p := player.NewPlayer(pd.ID, pd.Name, pd.IsAI)
-		p.Money = pd.Money
-		p.Position = pd.Position
-		p.InJail = pd.InJail
-		p.JailTurns = pd.JailTurns
-		p.Bankrupt = pd.Bankrupt
-		p.Properties = pd.Properties
-		p.GetOutOfJailCards = pd.GetOutOfJailCards
-		g.Players = append(g.Players, p)
-	}
-
-	g.Current = data.Current
-	g.Die1 = data.Die1
-	g.Die2 = data.Die2
-	g.Messages = data.Messages
-	g.State = StatePlaying
-	g.Phase = PhasePreRoll
-	g.Dialog = DialogNone
-	g.setupButtons()
-	g.updateButtonStates()
-	g.AddMessage("Game loaded!")
-	return true
-}
-
 // hudData builds an HUDData struct for the renderer.
 func (g *Game) hudData() render.HUDData {
 	data := render.HUDData{
-		CurrentPlayerID: g.currentPlayer().ID,
+		CurrentPlayerID: g.CurrentPlayer().ID,
 		Messages:        g.Messages,
 		Die1:            g.Die1,
 		Die2:            g.Die2,
 		Phase:           g.phaseString(),
+		Seed:            g.Rand.Seed(),
+		Jackpot:         -1,
+	}
+	if g.Rules.FreeParkingJackpot {
+		data.Jackpot = g.Jackpot
 	}
 	for _, p := range g.Players {
 		data.Players = append(data.Players, render.PlayerInfo{
-			ID:       p.ID,
-			Name:     p.Name,
-			Money:    p.Money,
-			Bankrupt: p.Bankrupt,
-			InJail:   p.InJail,
-			IsAI:     p.IsAI,
+			ID:         p.ID,
+			Name:       p.Name,
+			Money:      p.Money,
+			CashHidden: !g.cashVisible(p.ID),
+			Bankrupt:   p.Bankrupt,
+			InJail:     p.InJail,
+			IsAI:       p.IsAI,
+			Difficulty: p.Difficulty.String(),
+			Color:      p.Color,
 		})
 	}
 	return data
@@ -940,25 +656,38 @@ func (g *Game) tradePropsToggle(props []int, idx int) []int {
 }
 
 func (g *Game) phaseString() string {
-	switch g.Phase {
-	case PhasePreRoll:
-		return "Click [Roll Dice]"
-	case PhaseRolling:
+	if g.DiceRolling {
 		return "Rolling..."
-	case PhaseMoving:
+	}
+	if g.animating() {
 		return "Moving..."
-	case PhaseLanded:
-		return "Landed!"
-	case PhaseDialog:
+	}
+	if g.reconnecting {
+		return "Reconnecting..."
+	}
+	if g.standingIn(g.Actor()) {
+		return "AI playing for " + g.Players[g.Actor()].Name
+	}
+	if g.remoteActor() {
+		return "Waiting for " + g.Players[g.Actor()].Name
+	}
+	switch g.Phase {
+	case engine.PhasePreRoll:
+		return "Click [Roll Dice]"
+	case engine.PhaseBuyDecision, engine.PhaseIncomeTax:
 		return "Decision time"
-	case PhaseAuction:
+	case engine.PhaseAuction:
 		return "Auction"
-	case PhasePostAction:
+	case engine.PhaseTradeResponse:
+		return "Trade offer"
+	case engine.PhasePostAction:
 		return "Post-action"
-	case PhaseTurnEnd:
-		return "Turn ending"
-	case PhaseJailDecision:
+	case engine.PhaseJailDecision:
 		return "Jail decision"
+	case engine.PhaseDebt:
+		return "Raising cash"
+	case engine.PhaseTransfer:
+		return "Mortgaged transfer"
 	default:
 		return ""
 	}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/glow"
+)
+
+// humansLeft counts the human players at this screen still in the game.
+func (g *Game) humansLeft() int {
+	n := 0
+	for _, p := range g.Players {
+		if !p.IsAI && !p.Bankrupt && g.controls(p.ID) {
+			n++
+		}
+	}
+	return n
+}
+
+// handOffDue reports whether the screen stays covered until the human the
+// engine is waiting on takes it over. Auction bids are open, so they are
+// not worth passing the screen round for.
+func (g *Game) handOffDue() bool {
+	if !g.HandOff || g.Phase == engine.PhaseAuction || g.humansLeft() < 2 {
+		return false
+	}
+	actor := g.Actor()
+	return !g.Players[actor].IsAI && g.controls(actor) && actor != g.Viewer
+}
+
+// cashVisible reports whether a player's cash may be shown to the viewer.
+func (g *Game) cashVisible(id int) bool {
+	return !g.HiddenCash || id == g.Viewer
+}
+
+// cashText formats a player's cash, hiding it from other players when the
+// hidden cash option is on.
+func (g *Game) cashText(p *player.Player) string {
+	if !g.cashVisible(p.ID) {
+		return "??? MAD"
+	}
+	return fmt.Sprintf("%d MAD", p.Money)
+}
+
+// drawHandOff covers the game until the next human clicks.
+func (g *Game) drawHandOff(canvas *glow.Canvas) {
+	render.DrawMenuBackground(canvas, g.GameTimer)
+
+	p := g.Players[g.Actor()]
+	reason := "It's your turn"
+	switch g.Phase {
+	case engine.PhaseTradeResponse:
+		reason = fmt.Sprintf("%s has a trade offer for you", g.Players[g.PendingOffer.FromPlayer].Name)
+	case engine.PhaseTransfer:
+		reason = "You are receiving a mortgaged property"
+	case engine.PhaseDebt:
+		if p.ID != g.Current {
+			reason = "You owe money and must raise cash"
+		}
+	}
+
+	cx := canvas.Width() / 2
+	cy := canvas.Height() / 2
+	render.DrawTextCentered(canvas, "Pass to "+p.Name, cx+2, cy-58, glow.Color{R: 0, G: 0, B: 0}, 3)
+	render.DrawTextCentered(canvas, "Pass to "+p.Name, cx, cy-60, render.PlayerColors[p.Color], 3)
+	render.DrawTokenAt(canvas, p.ID, p.Color, cx, cy, 10)
+	render.DrawTextCentered(canvas, reason, cx, cy+30, render.TextLight, 1)
+	render.DrawTextCentered(canvas, "Click when ready", cx, cy+60, render.ZelligeGold, 2)
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/markov"
+	"github.com/AchrafSoltani/glow"
+)
+
+// HeatmapMode picks what the board overlay shows.
+type HeatmapMode int
+
+const (
+	HeatmapOff     HeatmapMode = iota
+	HeatmapLanding             // how often each space is landed on
+	HeatmapRent                // rent each lot earns per opponent turn
+)
+
+// cycleHeatmap switches to the next overlay, then back to none.
+func (g *Game) cycleHeatmap() {
+	g.Heatmap = (g.Heatmap + 1) % (HeatmapRent + 1)
+}
+
+// drawHeatmap tints the board with the current overlay.
+func (g *Game) drawHeatmap(canvas *glow.Canvas) {
+	odds := markov.For(g.Board)
+	var values [config.SpaceCount]float64
+	var labels [config.SpaceCount]string
+	title := ""
+
+	switch g.Heatmap {
+	case HeatmapLanding:
+		title = "Landings per turn (%)"
+		for i, p := range odds.PerTurn {
+			values[i] = p
+			if p > 0 {
+				labels[i] = fmt.Sprintf("%.1f", p*100)
+			}
+		}
+	case HeatmapRent:
+		title = "Rent per opponent turn (MAD)"
+		for i, p := range odds.PerTurn {
+			values[i] = p * float64(g.expectedRent(i))
+			if values[i] > 0 {
+				labels[i] = fmt.Sprintf("%.1f", values[i])
+			}
+		}
+	default:
+		return
+	}
+	g.BoardRenderer.DrawHeatmap(canvas, values, labels, title)
+}
+
+// expectedRent is what landing on an owned lot costs now, taking an
+// average roll of 7 for utilities; 0 if nobody collects.
+func (g *Game) expectedRent(i int) int {
+	prop := g.Board.Properties[i]
+	if !g.Board.IsProperty(i) || prop.OwnerID < 0 || prop.Mortgaged {
+		return 0
+	}
+	if g.Board.Spaces[i].Type == board.SpaceUtility {
+		if g.CountOwnedUtilities(prop.OwnerID) == 2 {
+			return 7 * 10
+		}
+		return 7 * 4
+	}
+	return g.CalculateRent(i)
+}
@@ -0,0 +1,305 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/lan"
+	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/MoroccanMonopoly/save"
+	"github.com/AchrafSoltani/glow"
+)
+
+// HostLAN hosts games on addr. Seats set to Remote on the setup screen go
+// to clients that join, in the order they joined.
+func (g *Game) HostLAN(addr string) error {
+	h, err := lan.Listen(addr)
+	if err != nil {
+		return err
+	}
+	g.Host = h
+	return nil
+}
+
+// JoinLAN connects to a host and waits in its lobby for a game to start.
+func (g *Game) JoinLAN(addr, name string) error {
+	c, err := lan.Join(addr, name)
+	if err != nil {
+		return err
+	}
+	g.Client = c
+	g.State = StateLobby
+	return nil
+}
+
+// leaveLAN disconnects from the host and returns to the menu with notice.
+func (g *Game) leaveLAN(notice string) {
+	g.Client.Close()
+	g.Client = nil
+	g.reconnecting = false
+	g.toMenu()
+	g.Notice = notice
+}
+
+// controls reports whether this instance makes player id's decisions:
+// everyone's when playing alone, all but the clients' seats when hosting,
+// and only its own seat when joined.
+func (g *Game) controls(id int) bool {
+	switch {
+	case g.Client != nil:
+		return id == g.Client.Seat && !g.reconnecting
+	case g.Host != nil:
+		return !g.Host.Remote(id)
+	}
+	return true
+}
+
+// remoteActor reports whether the engine is waiting on a human at another
+// machine.
+func (g *Game) remoteActor() bool {
+	actor := g.Actor()
+	return !g.controls(actor) && !g.Players[actor].IsAI
+}
+
+// standingIn reports whether the host's AI is playing for a client that
+// has dropped out.
+func (g *Game) standingIn(id int) bool {
+	return g.Host != nil && g.Host.Remote(id) && !g.Host.Connected(id)
+}
+
+// updateLAN takes in whatever arrived from the network. Actions are queued
+// for updatePlaying, which applies them once the board is ready.
+func (g *Game) updateLAN() {
+	for g.Host != nil {
+		in, ok := g.Host.Poll()
+		if !ok {
+			break
+		}
+		g.hostReceived(in)
+	}
+	for g.Client != nil {
+		in, ok := g.Client.Poll()
+		if !ok {
+			break
+		}
+		g.clientReceived(in)
+	}
+}
+
+func (g *Game) hostReceived(in lan.Inbound) {
+	p := in.Peer
+	switch {
+	case in.Err != nil:
+		if p.Seat >= 0 && g.State == StatePlaying {
+			g.AddMessage(p.Name + " lost connection; the AI plays until they return")
+			g.AddMessage("Press A to give the seat to the AI for good")
+		}
+	case in.Message.Type == lan.MsgHello && p.Seat >= 0:
+		// Back with the token of its seat
+		p.Send(lan.Message{Type: lan.MsgResume, Seat: p.Seat, State: g.shared()})
+		g.AddMessage(p.Name + " reconnected")
+	case in.Message.Type == lan.MsgHello:
+		if g.State == StatePlaying {
+			p.Refuse("the game has already started")
+		}
+	case in.Message.Type == lan.MsgAction:
+		g.netQueue = append(g.netQueue, in)
+	}
+}
+
+func (g *Game) clientReceived(in lan.Inbound) {
+	m := in.Message
+	switch {
+	case in.Err != nil && g.State == StatePlaying && g.Client.Token != "":
+		// Keep the seat and try to get back in
+		if !g.reconnecting {
+			g.AddMessage("Lost connection to the host; reconnecting... (ESC to leave)")
+		}
+		g.reconnecting = true
+		g.awaitingHost = false
+		g.netQueue = nil
+		g.Client.Reconnect()
+	case in.Err != nil:
+		g.leaveLAN("Lost connection to the host")
+	case m.Type == lan.MsgStart:
+		if m.State == nil || save.Validate(m.State) != nil {
+			g.leaveLAN("The host sent a game that could not be read")
+			return
+		}
+		g.beginGame(m.State.Restore())
+		g.Viewer = m.Seat
+		g.HiddenCash = m.State.HiddenCash
+	case m.Type == lan.MsgResume:
+		g.reconnecting = false
+		g.resync(m.State)
+		g.AddMessage("Reconnected to the host")
+	case m.Type == lan.MsgApplied, m.Type == lan.MsgState:
+		g.netQueue = append(g.netQueue, in)
+	case m.Type == lan.MsgReject:
+		if g.State == StateLobby || g.reconnecting {
+			g.leaveLAN("The host refused: " + m.Text)
+			return
+		}
+		g.AddMessage("The host refused: " + m.Text)
+		g.awaitingHost = false
+	}
+}
+
+// applyNetwork applies the next action or state received from the network.
+// It reports whether there was one, so each gets a frame to start
+// animating.
+func (g *Game) applyNetwork() bool {
+	if len(g.netQueue) == 0 {
+		return false
+	}
+	in := g.netQueue[0]
+	g.netQueue = g.netQueue[1:]
+	m := in.Message
+	if m.Type == lan.MsgState {
+		g.resync(m.State)
+		return true
+	}
+	if g.Client != nil {
+		if m.Player == g.Client.Seat {
+			g.awaitingHost = false
+		}
+		g.follow(m)
+		return true
+	}
+
+	// A client's move for the seat it controls
+	p := in.Peer
+	a, err := lan.DecodeAction(m.Action)
+	if err == nil {
+		err = g.Validate(p.Seat, a)
+	}
+	if err != nil {
+		p.Send(lan.Message{Type: lan.MsgReject, Text: err.Error()})
+		return true
+	}
+	g.apply(p.Seat, a)
+	return true
+}
+
+// follow takes the host's state after an action it applied, then plays
+// the events the action led to as if it had been applied here: the log,
+// sounds, statistics and dice animation all come from them.
+func (g *Game) follow(m lan.Message) {
+	p := g.CurrentPlayer()
+	from, wasJailed := p.Position, p.InJail
+	events, err := lan.DecodeEvents(m.Events)
+	if err != nil {
+		log.Printf("events from the host: %v", err)
+		events = nil
+	}
+	if !g.resync(m.State) {
+		return
+	}
+
+	rolled := false
+	for _, e := range events {
+		g.Stats.Record(e)
+		g.onEvent(e)
+		if _, ok := e.(engine.DiceRolled); ok {
+			rolled = true
+		}
+	}
+	if rolled {
+		g.startDiceAnim(g.Players[p.ID], from, wasJailed)
+	}
+}
+
+// relay sends clients an action the host has just applied, with the events
+// and state it led to.
+func (g *Game) relay(id int, a engine.Action, events []engine.Event) {
+	w, err := lan.EncodeAction(a)
+	if err != nil {
+		log.Printf("relay %T failed: %v", a, err)
+		return
+	}
+	we, err := lan.EncodeEvents(events)
+	if err != nil {
+		log.Printf("relay %T failed: %v", a, err)
+		return
+	}
+	g.Host.Broadcast(lan.Message{Type: lan.MsgApplied, Player: id, Action: w, Events: we, State: g.shared()})
+}
+
+// shared is the whole state as clients are sent it; Peer.Send trims it to
+// what each client may see.
+func (g *Game) shared() *save.SaveData {
+	data := save.FromState(g.GameState)
+	data.HiddenCash = g.HiddenCash
+	return data
+}
+
+// sendAction asks the host to apply an action for this client's seat. It
+// comes back relayed once the host accepts it.
+func (g *Game) sendAction(a engine.Action) bool {
+	w, err := lan.EncodeAction(a)
+	if err != nil {
+		log.Printf("send %T failed: %v", a, err)
+		return false
+	}
+	g.Client.Send(lan.Message{Type: lan.MsgAction, Action: w})
+	g.awaitingHost = true
+	return true
+}
+
+// releaseDropped hands every seat whose client has dropped out to the AI
+// for good.
+func (g *Game) releaseDropped() {
+	released := false
+	for _, p := range g.Players {
+		if g.standingIn(p.ID) && !p.Bankrupt {
+			g.Host.Release(p.ID)
+			p.IsAI = true
+			g.AddMessage(p.Name + " is now played by the AI")
+			released = true
+		}
+	}
+	if released {
+		g.Host.Broadcast(lan.Message{Type: lan.MsgState, State: g.shared()})
+	}
+}
+
+// resync replaces the local engine with the host's state, keeping the log
+// and statistics. It reports whether the state could be taken; if not, the
+// client leaves the game.
+func (g *Game) resync(host *save.SaveData) bool {
+	if host == nil || save.Validate(host) != nil {
+		g.leaveLAN("Lost step with the host")
+		return false
+	}
+	messages, stats := g.Messages, g.Stats
+	// A client's engine never applies actions, so the fresh subscribers
+	// attach adds hear nothing; follow plays the host's events instead
+	g.attach(host.Restore())
+	g.Messages, g.Stats = messages, stats
+	g.resetAnimation()
+	g.syncDialog()
+	g.updateButtonStates()
+	if g.IsOver() {
+		g.State = StateGameOver
+	}
+	return true
+}
+
+func (g *Game) keyLobby(key glow.Key) {
+	if key == glow.KeyEscape {
+		g.leaveLAN("")
+	}
+}
+
+func (g *Game) drawLobby(canvas *glow.Canvas) {
+	render.DrawMenuBackground(canvas, g.GameTimer)
+
+	cx := canvas.Width() / 2
+	render.DrawTextCentered(canvas, "LAN GAME", cx+2, 82, glow.Color{R: 0, G: 0, B: 0}, 3)
+	render.DrawTextCentered(canvas, "LAN GAME", cx, 80, render.ZelligeGreen, 3)
+
+	render.DrawTextCentered(canvas, fmt.Sprintf("Connected to %s", g.Client.Addr), cx, 160, render.TextLight, 1)
+	render.DrawTextCentered(canvas, "Waiting for the host to start the game...", cx, 190, render.ZelligeGold, 1)
+	render.DrawTextCentered(canvas, "ESC - Leave", cx, 240, render.TextLight, 1)
+}
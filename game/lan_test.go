@@ -0,0 +1,35 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/save"
+)
+
+func TestClientHiddenCash(t *testing.T) {
+	host := newTestGame(3, engine.StandardRules())
+	data := save.FromState(host.GameState)
+	data.HiddenCash = true
+
+	// A client at seat 0, sent the state as a LAN host sends it
+	g := newTestGame(3, engine.StandardRules())
+	g.Viewer, g.HiddenCash = 0, true
+	if !g.resync(data.Public()) {
+		t.Fatal("the client could not take the host's state")
+	}
+
+	offer := engine.TradeOffer{FromPlayer: 0, ToPlayer: 1, WantedMoney: 100}
+	if !g.IsLegal(0, engine.ProposeTrade{Offer: offer}) {
+		t.Error("an offer asking for cash was refused")
+	}
+
+	for _, p := range g.hudData().Players {
+		if p.CashHidden != (p.ID != 0) {
+			t.Errorf("player %d: cash hidden %t", p.ID, p.CashHidden)
+		}
+	}
+	if got := g.cashText(g.Players[1]); got != "??? MAD" {
+		t.Errorf("another player's cash shown as %q", got)
+	}
+}
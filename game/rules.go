@@ -1,219 +1,168 @@
 package game
 
 import (
-	"github.com/AchrafSoltani/MoroccanMonopoly/board"
-	"github.com/AchrafSoltani/MoroccanMonopoly/config"
-)
-
-// CanBuildOnGroup checks if a player can build on a colour group.
-// Requires: owns all properties in group, none mortgaged, even building rule.
-func (g *Game) CanBuildOnGroup(playerID int, group board.ColorGroup) bool {
-	if group == board.GroupNone {
-		return false
-	}
-	if !g.hasMonopoly(playerID, group) {
-		return false
-	}
-	spaces := g.Board.SpacesInGroup(group)
-	for _, idx := range spaces {
-		if g.Board.Properties[idx].Mortgaged {
-			return false
-		}
-	}
-	return true
-}
-
-// CanBuildOnSpace checks if a house/hotel can be built on a specific property.
-// Even building rule: difference between min and max houses in group <= 1.
-func (g *Game) CanBuildOnSpace(spaceIndex int) bool {
-	space := g.Board.Spaces[spaceIndex]
-	prop := g.Board.Properties[spaceIndex]
+	"fmt"
 
-	if space.Type != board.SpaceProperty {
-		return false
-	}
-	if prop.OwnerID < 0 || prop.Mortgaged {
-		return false
-	}
-	if !g.CanBuildOnGroup(prop.OwnerID, space.Group) {
-		return false
-	}
-	if prop.Houses >= config.HotelLevel {
-		return false
-	}
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/glow"
+)
 
-	// Check pool availability
-	if prop.Houses == config.HousesPerHotel {
-		// Upgrading to hotel
-		if g.Board.HotelPool <= 0 {
-			return false
-		}
-	} else {
-		if g.Board.HousePool <= 0 {
-			return false
-		}
-	}
+// Steps for the amounts on the house rules screen.
+const (
+	startingMoneyStep = 100
+	salaryStep        = 50
+	jailFineStep      = 10
+	mortgageRateStep  = 5
+	interestRateStep  = 5
+	maxJailTurns      = 5
+)
 
-	// Even building rule: this property must have the fewest houses in its group
-	minHouses := g.minHousesInGroup(space.Group)
-	return prop.Houses <= minHouses
+// houseRule is one line of the house rules screen. Change adjusts it by
+// one step in either direction; toggles ignore the direction.
+type houseRule struct {
+	Label  string
+	Value  func(r engine.RuleSet) string
+	Change func(r *engine.RuleSet, dir int)
 }
 
-// BuildHouse adds a house (or hotel) to a property.
-func (g *Game) BuildHouse(spaceIndex int) int {
-	space := g.Board.Spaces[spaceIndex]
-	prop := &g.Board.Properties[spaceIndex]
-
-	if prop.Houses == config.HousesPerHotel {
-		// Upgrade to hotel: return 4 houses to pool, take 1 hotel
-		g.Board.HousePool += config.HousesPerHotel
-		g.Board.HotelPool--
-		prop.Houses = config.HotelLevel
-	} else {
-		g.Board.HousePool--
-		prop.Houses++
+func onOff(b bool) string {
+	if b {
+		return "ON"
 	}
-
-	return space.HouseCost
+	return "OFF"
 }
 
-// SellHouse removes a house from a property (even sell-down rule).
-func (g *Game) CanSellHouseOnSpace(spaceIndex int) bool {
-	space := g.Board.Spaces[spaceIndex]
-	prop := g.Board.Properties[spaceIndex]
-
-	if space.Type != board.SpaceProperty {
-		return false
-	}
-	if prop.Houses <= 0 {
-		return false
+func toggle(field func(r *engine.RuleSet) *bool) func(*engine.RuleSet, int) {
+	return func(r *engine.RuleSet, dir int) {
+		b := field(r)
+		*b = !*b
 	}
-
-	// Even selling rule: this property must have the most houses in its group
-	maxHouses := g.maxHousesInGroup(space.Group)
-	return prop.Houses >= maxHouses
 }
 
-// SellHouse removes a house and returns half the house cost.
-func (g *Game) SellHouse(spaceIndex int) int {
-	space := g.Board.Spaces[spaceIndex]
-	prop := &g.Board.Properties[spaceIndex]
-
-	if prop.Houses == config.HotelLevel {
-		// Downgrade from hotel: return 1 hotel, take 4 houses
-		g.Board.HotelPool++
-		g.Board.HousePool -= config.HousesPerHotel
-		prop.Houses = config.HousesPerHotel
-	} else {
-		g.Board.HousePool++
-		prop.Houses--
-	}
-
-	return space.HouseCost / 2
+var houseRules = []houseRule{
+	{
+		Label: "Starting cash",
+		Value: func(r engine.RuleSet) string { return fmt.Sprintf("%d MAD", r.StartingMoney) },
+		Change: func(r *engine.RuleSet, dir int) {
+			r.StartingMoney = max(startingMoneyStep, r.StartingMoney+dir*startingMoneyStep)
+		},
+	},
+	{
+		Label: "GO salary",
+		Value: func(r engine.RuleSet) string { return fmt.Sprintf("%d MAD", r.GoSalary) },
+		Change: func(r *engine.RuleSet, dir int) {
+			r.GoSalary = max(0, r.GoSalary+dir*salaryStep)
+		},
+	},
+	{
+		Label: "Jail fine",
+		Value: func(r engine.RuleSet) string { return fmt.Sprintf("%d MAD", r.JailFine) },
+		Change: func(r *engine.RuleSet, dir int) {
+			r.JailFine = max(0, r.JailFine+dir*jailFineStep)
+		},
+	},
+	{
+		Label: "Turns in jail before the fine",
+		Value: func(r engine.RuleSet) string { return fmt.Sprint(r.MaxJailTurns) },
+		Change: func(r *engine.RuleSet, dir int) {
+			r.MaxJailTurns = min(maxJailTurns, max(1, r.MaxJailTurns+dir))
+		},
+	},
+	{
+		Label: "Mortgage value (of the price)",
+		Value: func(r engine.RuleSet) string { return fmt.Sprintf("%d%%", r.MortgageRate) },
+		Change: func(r *engine.RuleSet, dir int) {
+			r.MortgageRate = min(100, max(mortgageRateStep, r.MortgageRate+dir*mortgageRateStep))
+		},
+	},
+	{
+		Label: "Mortgage interest",
+		Value: func(r engine.RuleSet) string { return fmt.Sprintf("%d%%", r.InterestRate) },
+		Change: func(r *engine.RuleSet, dir int) {
+			r.InterestRate = max(0, r.InterestRate+dir*interestRateStep)
+		},
+	},
+	{
+		Label:  "Double salary for landing on GO",
+		Value:  func(r engine.RuleSet) string { return onOff(r.DoubleSalary) },
+		Change: toggle(func(r *engine.RuleSet) *bool { return &r.DoubleSalary }),
+	},
+	{
+		Label:  "Free Parking jackpot (taxes and fines)",
+		Value:  func(r engine.RuleSet) string { return onOff(r.FreeParkingJackpot) },
+		Change: toggle(func(r *engine.RuleSet) *bool { return &r.FreeParkingJackpot }),
+	},
+	{
+		Label:  "Auction declined properties",
+		Value:  func(r engine.RuleSet) string { return onOff(!r.NoAuctions) },
+		Change: toggle(func(r *engine.RuleSet) *bool { return &r.NoAuctions }),
+	},
+	{
+		Label:  "Collect rent while in jail",
+		Value:  func(r engine.RuleSet) string { return onOff(!r.NoRentInJail) },
+		Change: toggle(func(r *engine.RuleSet) *bool { return &r.NoRentInJail }),
+	},
+	{
+		Label:  "Build and sell houses evenly",
+		Value:  func(r engine.RuleSet) string { return onOff(r.EvenBuild) },
+		Change: toggle(func(r *engine.RuleSet) *bool { return &r.EvenBuild }),
+	},
 }
 
-// BuildableProperties returns all space indices where the player can build.
-func (g *Game) BuildableProperties(playerID int) []int {
-	var result []int
-	for _, idx := range g.Players[playerID].Properties {
-		if g.CanBuildOnSpace(idx) {
-			result = append(result, idx)
-		}
-	}
-	return result
+// openRulesScreen shows the house rules used for new games.
+func (g *Game) openRulesScreen() {
+	g.RulesCursor = 0
+	g.State = StateRules
 }
 
-// MortgageableProperties returns properties that can be mortgaged.
-func (g *Game) MortgageableProperties(playerID int) []int {
-	var result []int
-	for _, idx := range g.Players[playerID].Properties {
-		prop := g.Board.Properties[idx]
-		if !prop.Mortgaged && prop.Houses == 0 {
-			result = append(result, idx)
+func (g *Game) keyRules(key glow.Key) {
+	switch key {
+	case glow.KeyEscape, glow.KeyEnter:
+		g.toMenu()
+	case glow.KeyUp:
+		if g.RulesCursor > 0 {
+			g.RulesCursor--
 		}
-	}
-	return result
-}
-
-// UnmortgageableProperties returns mortgaged properties that can be unmortgaged.
-func (g *Game) UnmortgageableProperties(playerID int) []int {
-	var result []int
-	for _, idx := range g.Players[playerID].Properties {
-		prop := g.Board.Properties[idx]
-		if prop.Mortgaged {
-			cost := g.UnmortgageCost(idx)
-			if g.Players[playerID].Money >= cost {
-				result = append(result, idx)
-			}
+	case glow.KeyDown:
+		if g.RulesCursor < len(houseRules)-1 {
+			g.RulesCursor++
 		}
+	case glow.KeyLeft:
+		houseRules[g.RulesCursor].Change(&g.HouseRules, -1)
+	case glow.KeyRight, glow.KeySpace:
+		houseRules[g.RulesCursor].Change(&g.HouseRules, 1)
+	case glow.KeyS:
+		g.HouseRules = engine.StandardRules()
 	}
-	return result
 }
 
-// MortgageValue returns cash received for mortgaging.
-func (g *Game) MortgageValue(spaceIndex int) int {
-	return g.Board.Spaces[spaceIndex].Price * config.MortgageRate / 100
-}
+func (g *Game) drawRules(canvas *glow.Canvas) {
+	render.DrawMenuBackground(canvas, g.GameTimer)
 
-// UnmortgageCost returns cost to unmortgage.
-func (g *Game) UnmortgageCost(spaceIndex int) int {
-	mortgageVal := g.MortgageValue(spaceIndex)
-	return mortgageVal * config.UnmortgageRate / 100
-}
+	cx := canvas.Width() / 2
+	render.DrawTextCentered(canvas, "HOUSE RULES", cx+2, 82, glow.Color{R: 0, G: 0, B: 0}, 3)
+	render.DrawTextCentered(canvas, "HOUSE RULES", cx, 80, render.ZelligeGreen, 3)
 
-// MortgageProperty mortgages a property.
-func (g *Game) MortgageProperty(spaceIndex int) int {
-	g.Board.Properties[spaceIndex].Mortgaged = true
-	return g.MortgageValue(spaceIndex)
-}
-
-// UnmortgageProperty unmortgages a property.
-func (g *Game) UnmortgageProperty(spaceIndex int) int {
-	g.Board.Properties[spaceIndex].Mortgaged = false
-	return g.UnmortgageCost(spaceIndex)
-}
-
-func (g *Game) minHousesInGroup(group board.ColorGroup) int {
-	spaces := g.Board.SpacesInGroup(group)
-	min := 999
-	for _, idx := range spaces {
-		h := g.Board.Properties[idx].Houses
-		if h < min {
-			min = h
+	x := cx - 260
+	y := 140
+	for i, rule := range houseRules {
+		if i == g.RulesCursor {
+			canvas.DrawRect(x-8, y-6, 536, 22, render.ButtonBg)
+			canvas.DrawRectOutline(x-8, y-6, 536, 22, render.ZelligeGold)
 		}
+		render.DrawText(canvas, rule.Label, x, y, render.TextLight, 1)
+		render.DrawTextRight(canvas, rule.Value(g.HouseRules), x+520, y, render.TextGold, 1)
+		y += 30
 	}
-	return min
-}
 
-func (g *Game) maxHousesInGroup(group board.ColorGroup) int {
-	spaces := g.Board.SpacesInGroup(group)
-	max := -1
-	for _, idx := range spaces {
-		h := g.Board.Properties[idx].Houses
-		if h > max {
-			max = h
-		}
-	}
-	return max
-}
-
-// PlayerNetWorth calculates a player's total asset value.
-func (g *Game) PlayerNetWorth(playerID int) int {
-	p := g.Players[playerID]
-	total := p.Money
-	for _, idx := range p.Properties {
-		space := g.Board.Spaces[idx]
-		prop := g.Board.Properties[idx]
-		if prop.Mortgaged {
-			total += g.MortgageValue(idx)
-		} else {
-			total += space.Price
-		}
-		if prop.Houses > 0 && prop.Houses <= config.HousesPerHotel {
-			total += prop.Houses * space.HouseCost / 2
-		} else if prop.Houses == config.HotelLevel {
-			total += config.HousesPerHotel * space.HouseCost / 2
-		}
+	y += 10
+	if g.HouseRules.IsStandard() {
+		render.DrawTextCentered(canvas, "Standard rules", cx, y, render.MortgageColor, 1)
+	} else {
+		render.DrawTextCentered(canvas, "House rules in play", cx, y, render.ZelligeGold, 1)
 	}
-	return total
+	y += 30
+	help := "UP/DOWN - Select   LEFT/RIGHT - Change   S - Standard rules   ESC - Back"
+	render.DrawTextCentered(canvas, help, cx, y, render.TextLight, 1)
 }
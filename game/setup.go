@@ -0,0 +1,350 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/lan"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/glow"
+)
+
+// Seat is one player slot on the setup screen.
+type Seat struct {
+	Name       string
+	AI         bool
+	Remote     bool              // played by a LAN client
+	Difficulty player.Difficulty // for AI seats
+	Color      int               // index into render.PlayerColors
+}
+
+// defaultSeats is one human against three Normal AIs, in distinct colours.
+func defaultSeats() [config.MaxPlayers]Seat {
+	var seats [config.MaxPlayers]Seat
+	for i := range seats {
+		ai := i > 0
+		seats[i] = Seat{Name: defaultSeatName(i, ai), AI: ai, Color: i}
+	}
+	return seats
+}
+
+// defaultSeatName names a seat that has not been given a name.
+func defaultSeatName(seat int, ai bool) string {
+	if ai {
+		return fmt.Sprintf("AI Player %d", seat)
+	}
+	return fmt.Sprintf("Player %d", seat+1)
+}
+
+// The setup screen lists the player count, then one row per seat, then
+// the table options below.
+func (g *Game) setupRows() int { return g.SeatCount + 1 + len(g.tableOptions()) }
+
+// tableOption is an on/off setting below the seats.
+type tableOption struct {
+	Label   string
+	On, Off string
+	Value   *bool
+}
+
+func (g *Game) tableOptions() []tableOption {
+	return []tableOption{
+		{"Turn order", "Random", "Seat order", &g.ShuffleSeats},
+		{"Hand-off screen between humans", "ON", "OFF", &g.HandOff},
+		{"Hide other players' cash", "ON", "OFF", &g.HiddenCash},
+	}
+}
+
+// setupSeat returns the seat under the cursor, or -1 on the other rows.
+func (g *Game) setupSeat() int {
+	if g.SetupCursor >= 1 && g.SetupCursor <= g.SeatCount {
+		return g.SetupCursor - 1
+	}
+	return -1
+}
+
+// openSetupScreen shows the players for a new game, as last set up.
+func (g *Game) openSetupScreen() {
+	g.SetupCursor = 0
+	g.SetupNaming = false
+	g.Notice = ""
+	g.State = StateSetup
+}
+
+func (g *Game) keySetup(key glow.Key) {
+	if g.SetupNaming {
+		g.keySeatName(key)
+		return
+	}
+
+	seat := g.setupSeat()
+	switch key {
+	case glow.KeyEscape:
+		g.Notice = ""
+		g.toMenu()
+	case glow.KeyEnter:
+		g.startFromSetup()
+	case glow.KeyUp:
+		if g.SetupCursor > 0 {
+			g.SetupCursor--
+		}
+	case glow.KeyDown:
+		if g.SetupCursor < g.setupRows()-1 {
+			g.SetupCursor++
+		}
+	case glow.KeyLeft, glow.KeyRight:
+		dir := 1
+		if key == glow.KeyLeft {
+			dir = -1
+		}
+		switch {
+		case g.SetupCursor == 0:
+			g.SeatCount = min(config.MaxPlayers, max(config.MinPlayers, g.SeatCount+dir))
+		case seat >= 0:
+			g.cycleSeatKind(seat, dir)
+		default:
+			opt := g.tableOptions()[g.SetupCursor-g.SeatCount-1]
+			*opt.Value = !*opt.Value
+		}
+	case glow.KeyC:
+		if seat >= 0 {
+			g.cycleSeatColor(seat)
+		}
+	case glow.KeyN:
+		if seat >= 0 && !g.Seats[seat].Remote {
+			g.SetupNaming = true
+			g.SetupNameInput = g.Seats[seat].Name
+		}
+	}
+	// The option rows move with the player count
+	g.SetupCursor = min(g.SetupCursor, g.setupRows()-1)
+}
+
+func (g *Game) keySeatName(key glow.Key) {
+	switch key {
+	case glow.KeyEscape:
+		g.SetupNaming = false
+	case glow.KeyEnter:
+		if name := strings.TrimSpace(g.SetupNameInput); name != "" {
+			g.Seats[g.setupSeat()].Name = name
+		}
+		g.SetupNaming = false
+	default:
+		g.SetupNameInput = typeKey(g.SetupNameInput, key, maxNameLength)
+	}
+}
+
+// seatKinds lists what a seat can be, in the order Left/Right steps
+// through them: human, remote when hosting, then each AI level.
+func (g *Game) seatKinds() []Seat {
+	kinds := []Seat{{}}
+	if g.Host != nil {
+		kinds = append(kinds, Seat{Remote: true})
+	}
+	for _, d := range player.Difficulties {
+		kinds = append(kinds, Seat{AI: true, Difficulty: d})
+	}
+	return kinds
+}
+
+// cycleSeatKind steps a seat to the next or previous kind. A seat still
+// using its default name is renamed to match.
+func (g *Game) cycleSeatKind(i, dir int) {
+	s := &g.Seats[i]
+	kinds := g.seatKinds()
+	kind := 0
+	for j, k := range kinds {
+		if k.AI == s.AI && k.Remote == s.Remote && (!k.AI || k.Difficulty == s.Difficulty) {
+			kind = j
+		}
+	}
+	next := kinds[(kind+dir+len(kinds))%len(kinds)]
+
+	wasDefault := s.Name == defaultSeatName(i, s.AI)
+	s.AI, s.Remote = next.AI, next.Remote
+	if s.AI {
+		s.Difficulty = next.Difficulty
+	}
+	if wasDefault {
+		s.Name = defaultSeatName(i, s.AI)
+	}
+}
+
+// cycleSeatColor moves a seat on to the next colour no other seat has.
+func (g *Game) cycleSeatColor(i int) {
+	taken := make(map[int]bool)
+	for j, s := range g.Seats {
+		if j != i {
+			taken[s.Color] = true
+		}
+	}
+	c := g.Seats[i].Color
+	for {
+		c = (c + 1) % config.TokenColors
+		if !taken[c] {
+			break
+		}
+	}
+	g.Seats[i].Color = c
+}
+
+// setupPlayers creates the players for the seats in use.
+func (g *Game) setupPlayers() []*player.Player {
+	var players []*player.Player
+	for i, seat := range g.Seats[:g.SeatCount] {
+		p := player.NewPlayer(i, seat.Name, seat.AI)
+		p.Difficulty = seat.Difficulty
+		p.Color = seat.Color
+		players = append(players, p)
+	}
+	return players
+}
+
+// remotePeers pairs each remote seat in use with a LAN client, in the
+// order they joined; nil if there are not enough clients.
+func (g *Game) remotePeers() map[int]*lan.Peer {
+	peers := make(map[int]*lan.Peer)
+	if g.Host == nil {
+		return peers
+	}
+	lobby := g.Host.Lobby()
+	for i, seat := range g.Seats[:g.SeatCount] {
+		if !seat.Remote {
+			continue
+		}
+		if len(lobby) == 0 {
+			return nil
+		}
+		peers[i], lobby = lobby[0], lobby[1:]
+	}
+	return peers
+}
+
+// startFromSetup starts the game set up on the screen, sending it to LAN
+// clients when hosting.
+func (g *Game) startFromSetup() {
+	peers := g.remotePeers()
+	if peers == nil {
+		g.Notice = "Waiting for more players to join"
+		return
+	}
+	players := g.setupPlayers()
+	seated := make(map[*lan.Peer]*player.Player)
+	for i, peer := range peers {
+		players[i].Name = peer.Name
+		seated[peer] = players[i]
+	}
+
+	g.StartGame(players, g.ShuffleSeats)
+
+	if g.Host != nil {
+		// Seats are only final once the turn order is
+		seats := make(map[*lan.Peer]int)
+		for peer, p := range seated {
+			seats[peer] = p.ID
+		}
+		g.Host.Start(seats, g.shared())
+	}
+}
+
+func seatKind(s Seat) string {
+	switch {
+	case s.AI:
+		return "AI (" + s.Difficulty.String() + ")"
+	case s.Remote:
+		return "Remote"
+	}
+	return "Human"
+}
+
+func (g *Game) drawSetup(canvas *glow.Canvas) {
+	render.DrawMenuBackground(canvas, g.GameTimer)
+
+	cx := canvas.Width() / 2
+	render.DrawTextCentered(canvas, "NEW GAME", cx+2, 82, glow.Color{R: 0, G: 0, B: 0}, 3)
+	render.DrawTextCentered(canvas, "NEW GAME", cx, 80, render.ZelligeGreen, 3)
+
+	x := cx - 260
+	y := 140
+	row := func(i int) {
+		if i == g.SetupCursor {
+			canvas.DrawRect(x-8, y-6, 536, 22, render.ButtonBg)
+			canvas.DrawRectOutline(x-8, y-6, 536, 22, render.ZelligeGold)
+		}
+	}
+
+	row(0)
+	render.DrawText(canvas, "Players", x, y, render.TextLight, 1)
+	render.DrawTextRight(canvas, fmt.Sprintf("< %d >", g.SeatCount), x+520, y, render.TextGold, 1)
+	y += 40
+
+	// Remote seats show who will take them
+	lobby := g.joinedNames()
+	for i, seat := range g.Seats[:g.SeatCount] {
+		row(i + 1)
+		render.DrawTokenAt(canvas, i, seat.Color, x+6, y+3, 6)
+		name := seat.Name
+		if seat.Remote {
+			name = "(waiting for a player to join)"
+			if len(lobby) > 0 {
+				name, lobby = lobby[0], lobby[1:]
+			}
+		}
+		if i == g.setupSeat() && g.SetupNaming {
+			name = g.SetupNameInput + "_"
+		}
+		render.DrawText(canvas, name, x+24, y, render.PlayerColors[seat.Color], 1)
+		render.DrawText(canvas, render.PlayerColorNames[seat.Color], x+300, y, render.TextLight, 1)
+		render.DrawTextRight(canvas, seatKind(seat), x+520, y, render.TextGold, 1)
+		y += 30
+	}
+	y += 10
+
+	for i, opt := range g.tableOptions() {
+		row(g.SeatCount + 1 + i)
+		value := opt.Off
+		if *opt.Value {
+			value = opt.On
+		}
+		render.DrawText(canvas, opt.Label, x, y, render.TextLight, 1)
+		render.DrawTextRight(canvas, value, x+520, y, render.TextGold, 1)
+		y += 30
+	}
+	y += 10
+
+	if g.Host != nil {
+		joined := "nobody has joined yet"
+		if names := g.joinedNames(); len(names) > 0 {
+			joined = strings.Join(names, ", ")
+		}
+		render.DrawTextCentered(canvas, fmt.Sprintf("Hosting on %s - %s", g.Host.Addr(), joined), cx, y, render.ZelligeGold, 1)
+		y += 20
+	}
+	if g.Notice != "" {
+		render.DrawTextCentered(canvas, g.Notice, cx, y, render.ColorRed, 1)
+		y += 20
+	}
+	y += 10
+
+	if g.SetupNaming {
+		render.DrawTextCentered(canvas, "Type a name   ENTER - Confirm   ESC - Cancel", cx, y, render.TextLight, 1)
+		return
+	}
+	render.DrawTextCentered(canvas, "UP/DOWN - Select   LEFT/RIGHT - Change   N - Name   C - Colour", cx, y, render.TextLight, 1)
+	y += 20
+	render.DrawTextCentered(canvas, "ENTER - Start game   ESC - Back", cx, y, render.TextLight, 1)
+}
+
+// joinedNames lists the LAN clients waiting to play, in the order they
+// joined.
+func (g *Game) joinedNames() []string {
+	var names []string
+	if g.Host != nil {
+		for _, p := range g.Host.Lobby() {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,288 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/MoroccanMonopoly/save"
+	"github.com/AchrafSoltani/glow"
+)
+
+const (
+	slotRows      = 8  // slots visible at once on the load screen
+	maxNameLength = 24 // longest name that can be typed
+)
+
+// openLoadScreen lists the saved games on disk.
+func (g *Game) openLoadScreen() {
+	g.refreshSlots()
+	g.SlotCursor = 0
+	g.SlotRenaming = false
+	g.State = StateLoad
+}
+
+func (g *Game) refreshSlots() {
+	slots, err := save.ListSlots()
+	if err != nil {
+		log.Printf("list saves failed: %v", err)
+	}
+	g.Slots = slots
+	if g.SlotCursor >= len(g.Slots) {
+		g.SlotCursor = len(g.Slots) - 1
+	}
+	if g.SlotCursor < 0 {
+		g.SlotCursor = 0
+	}
+}
+
+func (g *Game) keyLoad(key glow.Key) {
+	if g.SlotRenaming {
+		g.keyRename(key)
+		return
+	}
+
+	switch key {
+	case glow.KeyEscape:
+		g.toMenu()
+	case glow.KeyUp:
+		if g.SlotCursor > 0 {
+			g.SlotCursor--
+		}
+	case glow.KeyDown:
+		if g.SlotCursor < len(g.Slots)-1 {
+			g.SlotCursor++
+		}
+	}
+
+	if len(g.Slots) == 0 {
+		return
+	}
+	slot := g.Slots[g.SlotCursor]
+	switch key {
+	case glow.KeyEnter:
+		if !g.loadGame(slot.ID) {
+			g.toMenu()
+		}
+	case glow.KeyD:
+		if err := save.DeleteSlot(slot.ID); err != nil {
+			log.Printf("delete save failed: %v", err)
+		}
+		g.refreshSlots()
+	case glow.KeyN:
+		if slot.Err == nil {
+			g.SlotRenaming = true
+			g.SlotNameInput = slot.Meta.Name
+		}
+	}
+}
+
+func (g *Game) keyRename(key glow.Key) {
+	switch key {
+	case glow.KeyEscape:
+		g.SlotRenaming = false
+	case glow.KeyEnter:
+		name := strings.TrimSpace(g.SlotNameInput)
+		if name != "" {
+			if err := save.RenameSlot(g.Slots[g.SlotCursor].ID, name); err != nil {
+				log.Printf("rename save failed: %v", err)
+			}
+		}
+		g.SlotRenaming = false
+		g.refreshSlots()
+	default:
+		g.SlotNameInput = typeKey(g.SlotNameInput, key, maxNameLength)
+	}
+}
+
+func (g *Game) drawLoad(canvas *glow.Canvas) {
+	render.DrawMenuBackground(canvas, g.GameTimer)
+
+	cx := canvas.Width() / 2
+	render.DrawTextCentered(canvas, "LOAD GAME", cx+2, 82, glow.Color{R: 0, G: 0, B: 0}, 3)
+	render.DrawTextCentered(canvas, "LOAD GAME", cx, 80, render.ZelligeGreen, 3)
+
+	if len(g.Slots) == 0 {
+		render.DrawTextCentered(canvas, "No saved games", cx, 160, render.TextLight, 1)
+		render.DrawTextCentered(canvas, "ESC - Back", cx, 200, render.TextLight, 1)
+		return
+	}
+
+	// Scroll so the selected slot stays visible
+	first := 0
+	if g.SlotCursor >= slotRows {
+		first = g.SlotCursor - slotRows + 1
+	}
+
+	x := cx - 260
+	y := 130
+	for i := first; i < len(g.Slots) && i < first+slotRows; i++ {
+		slot := g.Slots[i]
+		meta := slot.Meta
+		if i == g.SlotCursor {
+			canvas.DrawRect(x-8, y-6, 536, 48, render.ButtonBg)
+			canvas.DrawRectOutline(x-8, y-6, 536, 48, render.ZelligeGold)
+		}
+
+		name := meta.Name
+		if i == g.SlotCursor && g.SlotRenaming {
+			name = g.SlotNameInput + "_"
+		}
+		nameCol := render.TextLight
+		if meta.Autosave {
+			render.DrawText(canvas, "[AUTO]", x, y, render.ZelligeGold, 1)
+			render.DrawText(canvas, name, x+56, y, nameCol, 1)
+		} else {
+			render.DrawText(canvas, name, x, y, nameCol, 1)
+		}
+		render.DrawTextRight(canvas, meta.SavedAt.Format("2006-01-02 15:04"), x+520, y, render.TextGold, 1)
+
+		if slot.Err != nil {
+			render.DrawText(canvas, "Unreadable: "+slot.Err.Error(), x, y+16, render.ColorRed, 1)
+		} else {
+			var players []string
+			for _, p := range meta.Players {
+				if p.Bankrupt {
+					players = append(players, p.Name+" (out)")
+				} else {
+					players = append(players, fmt.Sprintf("%s %d", p.Name, p.NetWorth))
+				}
+			}
+			info := fmt.Sprintf("Round %d - %s", meta.Round, strings.Join(players, ", "))
+			render.DrawText(canvas, info, x, y+16, render.MortgageColor, 1)
+		}
+		y += 56
+	}
+
+	y += 10
+	help := "UP/DOWN - Select   ENTER - Load   N - Rename   D - Delete   ESC - Back"
+	if g.SlotRenaming {
+		help = "Type a name   ENTER - Confirm   ESC - Cancel"
+	}
+	render.DrawTextCentered(canvas, help, cx, y, render.TextLight, 1)
+}
+
+// saveGame writes the game to its slot, creating a new slot the first time.
+func (g *Game) saveGame() {
+	if g.SlotID == "" {
+		g.SlotID = save.NewSlotID()
+	}
+	data := g.snapshot()
+	data.Meta.Name = g.SlotName
+
+	if err := save.SaveSlot(g.SlotID, data); err != nil {
+		g.AddMessage("Save failed: " + err.Error())
+	} else {
+		g.SlotName = data.Meta.Name
+		g.AddMessage("Game saved!")
+	}
+}
+
+// autosave records the start of each turn, keeping a short rolling history.
+func (g *Game) autosave() {
+	if err := save.Autosave(g.snapshot()); err != nil {
+		log.Printf("autosave failed: %v", err)
+	}
+}
+
+// snapshot captures the engine state along with the open dialog, log and
+// statistics.
+func (g *Game) snapshot() *save.SaveData {
+	data := save.FromState(g.GameState)
+	data.Messages = g.Messages
+	data.Stats = save.StatsToData(g.Stats)
+	data.Dialog = int(g.Dialog)
+	data.HandOff, data.HiddenCash = g.HandOff, g.HiddenCash
+	if g.Dialog == DialogTrade {
+		data.TradeBuilder = &save.TradeBuilderData{
+			Stage: int(g.TradeStage),
+			Offer: save.OfferToData(g.tradeOffer()),
+		}
+	}
+	return data
+}
+
+// loadGame restores a saved game, resuming mid-turn exactly where the save
+// was made, including any open auction, trade or dialog.
+func (g *Game) loadGame(id string) bool {
+	data, err := save.LoadSlot(id)
+	if err != nil {
+		log.Printf("load failed: %v", err)
+		g.Notice = "Could not load save: " + err.Error()
+		var invalid *save.ValidationError
+		if errors.As(err, &invalid) {
+			g.Notice = "Save is corrupt: " + invalid.Problems[0]
+		}
+		return false
+	}
+	g.Notice = ""
+
+	// Saving a resumed autosave starts a new slot rather than overwriting it
+	g.SlotID, g.SlotName = id, data.Meta.Name
+	if data.Meta.Autosave {
+		g.SlotID, g.SlotName = "", ""
+	}
+
+	g.attach(data.Restore())
+	g.Messages = data.Messages
+	*g.Stats = save.DataToStats(data.Stats)
+	g.State = StatePlaying
+	g.Dialog = DialogType(data.Dialog)
+	g.HandOff, g.HiddenCash = data.HandOff, data.HiddenCash
+	g.Viewer = -1 // whoever is up is handed the screen again
+	g.TradePartner = -1
+	if tb := data.TradeBuilder; tb != nil && g.Dialog == DialogTrade {
+		offer := save.DataToOffer(tb.Offer)
+		g.TradeStage = TradeState(tb.Stage)
+		g.TradePartner = offer.ToPlayer
+		g.TradeOfferedProps = offer.OfferedProps
+		g.TradeWantedProps = offer.WantedProps
+		g.TradeOfferedMoney = offer.OfferedMoney
+		g.TradeWantedMoney = offer.WantedMoney
+		g.TradeOfferJailCard = offer.OfferedJailCards > 0
+		g.TradeWantJailCard = offer.WantedJailCards > 0
+	} else if g.Dialog == DialogTrade {
+		g.Dialog = DialogNone
+	}
+	g.resetAnimation()
+	g.setupButtons()
+	g.syncDialog()
+	g.updateButtonStates()
+	g.AddMessage("Game loaded!")
+	return true
+}
+
+// keyChars maps the keys that type into a text field.
+var keyChars = map[glow.Key]rune{
+	glow.KeySpace: ' ',
+	glow.Key0:     '0', glow.Key1: '1', glow.Key2: '2', glow.Key3: '3', glow.Key4: '4',
+	glow.Key5: '5', glow.Key6: '6', glow.Key7: '7', glow.Key8: '8', glow.Key9: '9',
+	glow.KeyA: 'a', glow.KeyB: 'b', glow.KeyC: 'c', glow.KeyD: 'd', glow.KeyE: 'e',
+	glow.KeyF: 'f', glow.KeyG: 'g', glow.KeyH: 'h', glow.KeyI: 'i', glow.KeyJ: 'j',
+	glow.KeyK: 'k', glow.KeyL: 'l', glow.KeyM: 'm', glow.KeyN: 'n', glow.KeyO: 'o',
+	glow.KeyP: 'p', glow.KeyQ: 'q', glow.KeyR: 'r', glow.KeyS: 's', glow.KeyT: 't',
+	glow.KeyU: 'u', glow.KeyV: 'v', glow.KeyW: 'w', glow.KeyX: 'x', glow.KeyY: 'y',
+	glow.KeyZ: 'z',
+}
+
+// typeKey applies a key press to a text field of at most max characters.
+// Key events carry no shift state, so each word is capitalised.
+func typeKey(text string, key glow.Key, max int) string {
+	if key == glow.KeyBackspace {
+		if len(text) > 0 {
+			text = text[:len(text)-1]
+		}
+		return text
+	}
+	ch, ok := keyChars[key]
+	if !ok || len(text) >= max {
+		return text
+	}
+	if text == "" || strings.HasSuffix(text, " ") {
+		ch = unicode.ToUpper(ch)
+	}
+	return text + string(ch)
+}
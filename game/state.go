@@ -6,26 +6,11 @@ type GameState int
 const (
 	StateMenu    GameState = iota
 	StateSetup             // player setup screen
+	StateLoad              // save slot list
 	StatePlaying           // main gameplay
 	StateGameOver
-)
-
-// TurnPhase represents the phase within a player's turn.
-type TurnPhase int
-
-const (
-	PhasePreRoll      TurnPhase = iota
-	PhaseJailDecision           // choosing how to exit jail
-	PhaseRolling                // dice animation
-	PhaseMoving                 // token moving animation
-	PhaseLanded                 // just landed, evaluate space
-	PhaseDialog                 // showing buy/rent/card/tax dialog
-	PhaseAuction                // auction in progress
-	PhasePostAction             // post-landing, may roll again (doubles)
-	PhaseTurnEnd                // turn ending, advance to next player
-	PhaseTrade                  // trade dialog open
-	PhaseBuild                  // build dialog open
-	PhaseMortgage               // mortgage dialog open
+	StateRules // house rules for new games
+	StateLobby // joined a LAN host, waiting for its game to start
 )
 
 // DialogType identifies which dialog is showing.
@@ -47,4 +32,45 @@ const (
 	DialogAuction
 	DialogBankruptcy
 	DialogGameOver
+	DialogMortgagedTransfer
 )
+
+// String returns a short name for the dialog.
+func (d DialogType) String() string {
+	switch d {
+	case DialogNone:
+		return "none"
+	case DialogBuyProperty:
+		return "buy property"
+	case DialogPayRent:
+		return "pay rent"
+	case DialogChanceCard:
+		return "chance card"
+	case DialogCommunityCard:
+		return "community card"
+	case DialogPayTax:
+		return "pay tax"
+	case DialogIncomeTax:
+		return "income tax"
+	case DialogJailOptions:
+		return "jail options"
+	case DialogBuild:
+		return "build"
+	case DialogMortgage:
+		return "mortgage"
+	case DialogTrade:
+		return "trade"
+	case DialogTradeReceived:
+		return "trade received"
+	case DialogAuction:
+		return "auction"
+	case DialogBankruptcy:
+		return "bankruptcy"
+	case DialogGameOver:
+		return "game over"
+	case DialogMortgagedTransfer:
+		return "mortgaged transfer"
+	default:
+		return "unknown"
+	}
+}
@@ -3,21 +3,9 @@ package game
 import (
 	"fmt"
 
-	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
 )
 
-// TradeOffer represents a trade proposal.
-type TradeOffer struct {
-	FromPlayer int
-	ToPlayer   int
-	OfferedProps []int // space indices offered
-	WantedProps  []int // space indices wanted
-	OfferedMoney int
-	WantedMoney  int
-	OfferedJailCards int
-	WantedJailCards  int
-}
-
 // TradeState tracks the trading UI state.
 type TradeState int
 
@@ -29,7 +17,7 @@ const (
 
 // openTradeDialog starts the trade flow.
 func (g *Game) openTradeDialog() {
-	p := g.currentPlayer()
+	p := g.Players[g.Actor()]
 	// Find other alive players
 	var partners []int
 	for _, other := range g.Players {
@@ -50,114 +38,145 @@ func (g *Game) openTradeDialog() {
 	g.TradeOfferJailCard = false
 	g.TradeWantJailCard = false
 	g.TradeStage = TradeSelectPartner
-	g.PendingOffer = nil
 	g.Dialog = DialogTrade
-	g.Phase = PhaseTrade
-
-	g.SelectableSpaces = partners
-	g.SelectedSpace = -1
 }
 
-// executeTrade performs the trade between two players.
-func (g *Game) executeTrade(offer TradeOffer) {
-	from := g.Players[offer.FromPlayer]
-	to := g.Players[offer.ToPlayer]
-
-	// Transfer offered properties
-	for _, idx := range offer.OfferedProps {
-		from.RemoveProperty(idx)
-		to.AddProperty(idx)
-		g.Board.Properties[idx].OwnerID = offer.ToPlayer
-	}
+// cancelTrade abandons the offer being built.
+func (g *Game) cancelTrade() {
+	g.TradePartner = -1
+	g.closeDialog()
+}
 
-	// Transfer wanted properties
-	for _, idx := range offer.WantedProps {
-		to.RemoveProperty(idx)
-		from.AddProperty(idx)
-		g.Board.Properties[idx].OwnerID = offer.FromPlayer
+// tradeOffer assembles the offer being built into an engine.TradeOffer.
+func (g *Game) tradeOffer() engine.TradeOffer {
+	offeredJail := 0
+	if g.TradeOfferJailCard {
+		offeredJail = 1
 	}
-
-	// Transfer money
-	if offer.OfferedMoney > 0 {
-		from.Pay(offer.OfferedMoney)
-		to.Receive(offer.OfferedMoney)
+	wantedJail := 0
+	if g.TradeWantJailCard {
+		wantedJail = 1
 	}
-	if offer.WantedMoney > 0 {
-		to.Pay(offer.WantedMoney)
-		from.Receive(offer.WantedMoney)
+	return engine.TradeOffer{
+		FromPlayer:       g.Actor(),
+		ToPlayer:         g.TradePartner,
+		OfferedProps:     g.TradeOfferedProps,
+		WantedProps:      g.TradeWantedProps,
+		OfferedMoney:     g.TradeOfferedMoney,
+		WantedMoney:      g.TradeWantedMoney,
+		OfferedJailCards: offeredJail,
+		WantedJailCards:  wantedJail,
 	}
-
-	// Transfer jail cards
-	from.GetOutOfJailCards -= offer.OfferedJailCards
-	to.GetOutOfJailCards += offer.OfferedJailCards
-	to.GetOutOfJailCards -= offer.WantedJailCards
-	from.GetOutOfJailCards += offer.WantedJailCards
-
-	g.AddMessage(fmt.Sprintf("Trade completed between %s and %s", from.Name, to.Name))
 }
 
-// aiEvaluateTrade decides if the AI should accept a trade offer.
-func (g *Game) aiEvaluateTrade(offer TradeOffer) bool {
-	aiID := offer.ToPlayer
-	received := offer.OfferedMoney
-	given := offer.WantedMoney
-
-	for _, idx := range offer.OfferedProps {
-		space := g.Board.Spaces[idx]
-		value := space.Price
-		// Weight higher if receiving this property would complete AI's monopoly
-		if g.almostMonopoly(aiID, space.Group) {
-			value = value * 18 / 10 // 1.8x
+// tradeView builds the trade dialog for the current builder stage. Only the
+// final Propose option reaches the engine; the rest edit the offer locally.
+func (g *Game) tradeView() dialogView {
+	p := g.Players[g.Actor()]
+	cancel := uiOption("Cancel", true, g.cancelTrade)
+
+	switch g.TradeStage {
+	case TradeSelectPartner:
+		var opts []dialogOption
+		for _, other := range g.Players {
+			if other.ID != p.ID && !other.Bankrupt {
+				id := other.ID
+				label := fmt.Sprintf("%s (%s, %d props)", other.Name, g.cashText(other), len(other.Properties))
+				opts = append(opts, uiOption(label, true, func() {
+					g.TradePartner = id
+					g.TradeStage = TradeSelectOffer
+				}))
+			}
+		}
+		return dialogView{
+			Title:   "Trade - Select Partner",
+			Lines:   []string{"Who do you want to trade with?"},
+			Options: append(opts, cancel),
 		}
-		received += value
-	}
-	for _, idx := range offer.WantedProps {
-		space := g.Board.Spaces[idx]
-		value := space.Price
 
-		// Reject if this would complete opponent's monopoly
-		if g.wouldCompleteMonopoly(offer.FromPlayer, space.Group) {
-			return false
+	case TradeSelectOffer:
+		partner := g.Players[g.TradePartner]
+		var opts []dialogOption
+
+		// Own properties to offer, then partner properties to request
+		for _, idx := range p.Properties {
+			idx := idx
+			prefix := "[ ] Offer: "
+			if g.tradePropsContains(g.TradeOfferedProps, idx) {
+				prefix = "[X] Offer: "
+			}
+			opts = append(opts, uiOption(prefix+g.Board.Spaces[idx].Name, g.CanTradeProperty(idx), func() {
+				g.TradeOfferedProps = g.tradePropsToggle(g.TradeOfferedProps, idx)
+			}))
 		}
-		// Weight higher if AI almost has a monopoly in that group (reluctant to give up)
-		if g.almostMonopoly(aiID, space.Group) {
-			value = value * 15 / 10 // 1.5x
+		for _, idx := range partner.Properties {
+			idx := idx
+			prefix := "[ ] Want:  "
+			if g.tradePropsContains(g.TradeWantedProps, idx) {
+				prefix = "[X] Want:  "
+			}
+			opts = append(opts, uiOption(prefix+g.Board.Spaces[idx].Name, g.CanTradeProperty(idx), func() {
+				g.TradeWantedProps = g.tradePropsToggle(g.TradeWantedProps, idx)
+			}))
 		}
-		given += value
-	}
 
-	return received >= given
-}
+		// Money
+		opts = append(opts,
+			uiOption(fmt.Sprintf("Offer +50 MAD (now: %d)", g.TradeOfferedMoney), true, func() { g.TradeOfferedMoney += 50 }),
+			uiOption(fmt.Sprintf("Offer -50 MAD (now: %d)", g.TradeOfferedMoney), g.TradeOfferedMoney >= 50, func() { g.TradeOfferedMoney -= 50 }),
+			uiOption(fmt.Sprintf("Want +50 MAD (now: %d)", g.TradeWantedMoney), true, func() { g.TradeWantedMoney += 50 }),
+			uiOption(fmt.Sprintf("Want -50 MAD (now: %d)", g.TradeWantedMoney), g.TradeWantedMoney >= 50, func() { g.TradeWantedMoney -= 50 }),
+		)
+
+		// Jail card toggles
+		if p.GetOutOfJailCards > 0 {
+			label := "[ ] Offer Jail Card"
+			if g.TradeOfferJailCard {
+				label = "[X] Offer Jail Card"
+			}
+			opts = append(opts, uiOption(label, true, func() { g.TradeOfferJailCard = !g.TradeOfferJailCard }))
+		}
+		if partner.GetOutOfJailCards > 0 {
+			label := "[ ] Want Jail Card"
+			if g.TradeWantJailCard {
+				label = "[X] Want Jail Card"
+			}
+			opts = append(opts, uiOption(label, true, func() { g.TradeWantJailCard = !g.TradeWantJailCard }))
+		}
 
-// almostMonopoly returns true if the player owns all but one property in a group.
-func (g *Game) almostMonopoly(playerID int, group board.ColorGroup) bool {
-	if group == board.GroupNone {
-		return false
-	}
-	spaces := g.Board.SpacesInGroup(group)
-	if len(spaces) == 0 {
-		return false
-	}
-	owned := 0
-	for _, idx := range spaces {
-		if g.Board.Properties[idx].OwnerID == playerID {
-			owned++
+		// The engine decides whether the offer as built could be proposed
+		valid := g.IsLegal(g.Actor(), engine.ProposeTrade{Offer: g.tradeOffer()})
+		opts = append(opts,
+			uiOption("Propose Trade >>", valid, func() { g.TradeStage = TradeConfirm }),
+			cancel,
+		)
+		return dialogView{
+			Title:   "Trade - Build Offer",
+			Lines:   []string{fmt.Sprintf("Building offer with %s:", partner.Name)},
+			Options: opts,
 		}
-	}
-	return owned == len(spaces)-1
-}
 
-// wouldCompleteMonopoly checks if giving a player a property would complete their monopoly.
-func (g *Game) wouldCompleteMonopoly(playerID int, group board.ColorGroup) bool {
-	if group == board.GroupNone {
-		return false
-	}
-	spaces := g.Board.SpacesInGroup(group)
-	owned := 0
-	for _, idx := range spaces {
-		if g.Board.Properties[idx].OwnerID == playerID {
-			owned++
+	default: // TradeConfirm
+		offer := g.tradeOffer()
+		partner := g.Players[g.TradePartner]
+		lines := []string{fmt.Sprintf("Trade with %s:", partner.Name), "--- You give ---"}
+		lines = append(lines, g.offerLines(offer.OfferedProps, offer.OfferedMoney, offer.OfferedJailCards)...)
+		lines = append(lines, "--- You get ---")
+		lines = append(lines, g.offerLines(offer.WantedProps, offer.WantedMoney, offer.WantedJailCards)...)
+		lines = append(lines, g.transferLines("You pay", offer.WantedProps)...)
+		lines = append(lines, g.transferLines(partner.Name+" pays", offer.OfferedProps)...)
+
+		// The partner answers through DialogTradeReceived (or the AI)
+		propose := g.actionOption("Propose Trade", engine.ProposeTrade{Offer: offer})
+		propose.Then = func() { g.TradePartner = -1 }
+		return dialogView{
+			Title: "Confirm Trade",
+			Lines: lines,
+			Options: []dialogOption{
+				propose,
+				uiOption("Go Back", true, func() { g.TradeStage = TradeSelectOffer }),
+				cancel,
+			},
 		}
 	}
-	return owned >= len(spaces)-1
 }
@@ -1,1082 +1,384 @@
 package game
 
 import (
-	"fmt"
-	"math/rand"
-	"sort"
-
-	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/ai"
 	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
 	"github.com/AchrafSoltani/MoroccanMonopoly/player"
 	"github.com/AchrafSoltani/MoroccanMonopoly/render"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
 )
 
 // updatePlaying handles the main gameplay loop.
 func (g *Game) updatePlaying(dt float64) {
-	p := g.currentPlayer()
-	if p == nil {
+	if g.CurrentPlayer() == nil {
 		return
 	}
 
-	// AI auto-actions
-	if p.IsAI {
-		g.updateAI(dt)
+	if g.animating() {
+		g.updateAnimation(dt)
+		g.updateButtonStates()
 		return
 	}
 
-	switch g.Phase {
-	case PhasePreRoll:
-		// If in jail, show jail options
-		if p.InJail {
-			g.Phase = PhaseJailDecision
-			g.Dialog = DialogJailOptions
-		}
-	case PhaseJailDecision:
-		// Waiting for player input (handled in button clicks)
-	case PhaseRolling:
-		g.updateDiceAnim(dt)
-	case PhaseMoving:
-		g.updateTokenMove(dt)
-	case PhaseLanded:
-		g.resolveLanding()
-	case PhaseTurnEnd:
-		g.endTurn()
-	}
+	g.playEvents()
 
-	// Handle button clicks
-	if g.MouseClicked {
-		g.handleDialogClicks()
-		g.handleButtonClicks()
+	// Moves from the network, one per frame so each can animate
+	if g.applyNetwork() {
+		return
 	}
-}
-
-// handleButtonClicks processes clicks on the action buttons.
-func (g *Game) handleButtonClicks() {
-	for i, btn := range g.Buttons {
-		if !btn.Visible || !btn.Enabled || !btn.Contains(g.MouseX, g.MouseY) {
-			continue
-		}
-		switch i {
-		case 0: // Roll Dice
-			if g.Phase == PhasePreRoll {
-				g.startDiceRoll()
-			}
-		case 1: // Buy
-			if g.Phase == PhaseDialog && g.Dialog == DialogBuyProperty {
-				g.buyProperty()
-			}
-		case 2: // Auction
-			if g.Phase == PhaseDialog && g.Dialog == DialogBuyProperty {
-				g.declineBuy()
-			}
-		case 3: // Build
-			if g.Phase == PhasePreRoll || g.Phase == PhasePostAction {
-				g.openBuildDialog()
-			}
-		case 4: // Mortgage
-			if g.Phase == PhasePreRoll || g.Phase == PhasePostAction {
-				g.openMortgageDialog()
-			}
-		case 5: // Trade
-			if g.Phase == PhasePreRoll || g.Phase == PhasePostAction {
-				g.openTradeDialog()
-			}
-		case 6: // End Turn
-			if g.Phase == PhasePostAction {
-				g.endTurn()
-			}
+	if !g.controls(g.Actor()) {
+		// The AI stands in for a client that has dropped out
+		if g.standingIn(g.Actor()) {
+			g.updateAI(dt)
 		}
+		g.updateButtonStates()
+		return
 	}
-}
 
-// handleDialogClicks processes clicks on dialog buttons.
-func (g *Game) handleDialogClicks() {
-	if g.DialogHovered == render.DialogNoHover {
+	// AI auto-actions for whoever the engine is waiting on
+	if g.Players[g.Actor()].IsAI {
+		g.updateAI(dt)
 		return
 	}
 
-	switch g.Dialog {
-	case DialogBuyProperty:
-		switch g.DialogHovered {
-		case 0: // Buy
-			g.buyProperty()
-		case 1: // Decline/Auction
-			g.declineBuy()
-		}
-
-	case DialogJailOptions:
-		p := g.currentPlayer()
-		switch g.DialogHovered {
-		case 0: // Pay fine
-			if p.Money >= config.JailFine {
-				p.Pay(config.JailFine)
-				p.InJail = false
-				p.JailTurns = 0
-				g.Dialog = DialogNone
-				g.Phase = PhasePreRoll
-				g.AddMessage(fmt.Sprintf("%s paid %d MAD to get out of jail", p.Name, config.JailFine))
-			}
-		case 1: // Use card
-			if p.GetOutOfJailCards > 0 {
-				p.GetOutOfJailCards--
-				p.InJail = false
-				p.JailTurns = 0
-				g.Dialog = DialogNone
-				g.Phase = PhasePreRoll
-				g.AddMessage(fmt.Sprintf("%s used Get Out of Jail Free card", p.Name))
-			}
-		case 2: // Roll doubles
-			g.Dialog = DialogNone
-			g.startDiceRoll()
-		}
-
-	case DialogIncomeTax:
-		p := g.currentPlayer()
-		tenPercent := g.PlayerNetWorth(p.ID) / 10
-		switch g.DialogHovered {
-		case 0: // Pay 200 MAD flat
-			g.AddMessage(fmt.Sprintf("%s pays 200 MAD income tax (flat)", p.Name))
-			g.payDebt(p, nil, 200)
-		case 1: // Pay 10%
-			g.AddMessage(fmt.Sprintf("%s pays %d MAD income tax (10%%)", p.Name, tenPercent))
-			g.payDebt(p, nil, tenPercent)
-		}
-		g.Dialog = DialogNone
-		g.Phase = PhasePostAction
-
-	case DialogBuild:
-		if g.DialogHovered == -1 {
-			// Cancel
-			g.Dialog = DialogNone
-			g.Phase = PhasePostAction
-			g.updateButtonStates()
-		} else if g.DialogHovered >= 0 && g.DialogHovered < len(g.SelectableSpaces) {
-			idx := g.SelectableSpaces[g.DialogHovered]
-			p := g.currentPlayer()
-			space := g.Board.Spaces[idx]
-			cost := g.BuildHouse(idx)
-			p.Pay(cost)
-			level := g.Board.Properties[idx].Houses
-			levelName := fmt.Sprintf("%d house(s)", level)
-			if level == config.HotelLevel {
-				levelName = "hotel"
-			}
-			g.AddMessage(fmt.Sprintf("%s built on %s (%s, -%d MAD)", p.Name, space.Name, levelName, cost))
-			// Refresh buildable list
-			buildable := g.BuildableProperties(p.ID)
-			if len(buildable) == 0 {
-				g.Dialog = DialogNone
-				g.Phase = PhasePostAction
-				g.updateButtonStates()
-			} else {
-				g.SelectableSpaces = buildable
-			}
-		}
-
-	case DialogMortgage:
-		if g.DialogHovered == -1 {
-			g.Dialog = DialogNone
-			g.Phase = PhasePostAction
+	// A human other than the one at the screen has to take it over first
+	if g.handOffDue() {
+		if g.MouseClicked {
+			g.Viewer = g.Actor()
 			g.updateButtonStates()
-		} else if g.DialogHovered >= 0 && g.DialogHovered < len(g.SelectableSpaces) {
-			idx := g.SelectableSpaces[g.DialogHovered]
-			p := g.currentPlayer()
-			space := g.Board.Spaces[idx]
-			prop := g.Board.Properties[idx]
-			if prop.Mortgaged {
-				cost := g.UnmortgageProperty(idx)
-				p.Pay(cost)
-				g.AddMessage(fmt.Sprintf("%s unmortgaged %s (-%d MAD)", p.Name, space.Name, cost))
-			} else {
-				val := g.MortgageProperty(idx)
-				p.Receive(val)
-				g.AddMessage(fmt.Sprintf("%s mortgaged %s (+%d MAD)", p.Name, space.Name, val))
-			}
-			// Refresh list
-			mortgageable := g.MortgageableProperties(p.ID)
-			unmortgageable := g.UnmortgageableProperties(p.ID)
-			all := append(mortgageable, unmortgageable...)
-			if len(all) == 0 {
-				g.Dialog = DialogNone
-				g.Phase = PhasePostAction
-				g.updateButtonStates()
-			} else {
-				g.SelectableSpaces = all
-			}
 		}
-
-	case DialogAuction:
-		g.handleAuctionClick()
-
-	case DialogTrade:
-		if g.DialogHovered == -1 {
-			// Cancel at any stage
-			g.Dialog = DialogNone
-			g.Phase = PhasePostAction
-			g.TradePartner = -1
-			g.updateButtonStates()
-			return
-		}
-		switch g.TradeStage {
-		case TradeSelectPartner:
-			g.TradePartner = g.DialogHovered
-			g.TradeStage = TradeSelectOffer
-		case TradeSelectOffer:
-			idx := g.DialogHovered
-			switch {
-			case idx >= 0 && idx < 1000:
-				// Toggle own property
-				g.TradeOfferedProps = g.tradePropsToggle(g.TradeOfferedProps, idx)
-			case idx >= 1000 && idx < 2000:
-				// Toggle partner property
-				g.TradeWantedProps = g.tradePropsToggle(g.TradeWantedProps, idx-1000)
-			case idx == 2000:
-				g.TradeOfferedMoney += 50
-			case idx == 2001:
-				if g.TradeOfferedMoney >= 50 {
-					g.TradeOfferedMoney -= 50
-				}
-			case idx == 2002:
-				g.TradeWantedMoney += 50
-			case idx == 2003:
-				if g.TradeWantedMoney >= 50 {
-					g.TradeWantedMoney -= 50
-				}
-			case idx == 2004:
-				g.TradeOfferJailCard = !g.TradeOfferJailCard
-			case idx == 2005:
-				g.TradeWantJailCard = !g.TradeWantJailCard
-			case idx == 3000:
-				// Move to confirm stage
-				g.TradeStage = TradeConfirm
-			}
-		case TradeConfirm:
-			switch g.DialogHovered {
-			case 0: // Propose
-				offeredJail := 0
-				if g.TradeOfferJailCard {
-					offeredJail = 1
-				}
-				wantedJail := 0
-				if g.TradeWantJailCard {
-					wantedJail = 1
-				}
-				offer := TradeOffer{
-					FromPlayer:       g.currentPlayer().ID,
-					ToPlayer:         g.TradePartner,
-					OfferedProps:     g.TradeOfferedProps,
-					WantedProps:      g.TradeWantedProps,
-					OfferedMoney:     g.TradeOfferedMoney,
-					WantedMoney:      g.TradeWantedMoney,
-					OfferedJailCards: offeredJail,
-					WantedJailCards:  wantedJail,
-				}
-				partner := g.Players[g.TradePartner]
-				if partner.IsAI {
-					if g.aiEvaluateTrade(offer) {
-						g.executeTrade(offer)
-					} else {
-						g.AddMessage(fmt.Sprintf("%s declined the trade", partner.Name))
-					}
-					g.Dialog = DialogNone
-					g.Phase = PhasePostAction
-					g.TradePartner = -1
-					g.updateButtonStates()
-				} else {
-					// Human-to-human: show offer to partner for acceptance
-					g.PendingOffer = &offer
-					g.Dialog = DialogTradeReceived
-				}
-			case 1: // Go back
-				g.TradeStage = TradeSelectOffer
-			}
-		}
-
-	case DialogTradeReceived:
-		if g.PendingOffer != nil {
-			switch g.DialogHovered {
-			case 0: // Accept
-				g.executeTrade(*g.PendingOffer)
-				g.PendingOffer = nil
-				g.Dialog = DialogNone
-				g.Phase = PhasePostAction
-				g.TradePartner = -1
-				g.updateButtonStates()
-			case 1: // Decline
-				g.AddMessage(fmt.Sprintf("%s declined the trade", g.Players[g.PendingOffer.ToPlayer].Name))
-				g.PendingOffer = nil
-				g.Dialog = DialogNone
-				g.Phase = PhasePostAction
-				g.TradePartner = -1
-				g.updateButtonStates()
-			}
-		}
-	}
-}
-
-// startDiceRoll begins the dice animation.
-func (g *Game) startDiceRoll() {
-	g.Phase = PhaseRolling
-	g.DiceRolling = true
-	g.DiceAnimTimer = 0
-	g.Die1 = rand.Intn(6) + 1
-	g.Die2 = rand.Intn(6) + 1
-	g.Audio.PlayDiceRoll()
-}
-
-// updateDiceAnim advances the dice rolling animation.
-func (g *Game) updateDiceAnim(dt float64) {
-	g.DiceAnimTimer += dt
-	if g.DiceAnimTimer >= config.DiceAnimDuration {
-		g.DiceRolling = false
-		g.Doubles = g.Die1 == g.Die2
-		if g.Doubles {
-			g.DoublesCount++
-		}
-
-		p := g.currentPlayer()
-		total := g.Die1 + g.Die2
-		g.AddMessage(fmt.Sprintf("%s rolled %d + %d = %d", p.Name, g.Die1, g.Die2, total))
-
-		if g.Doubles {
-			g.AddMessage("Doubles!")
-		}
-
-		// Check for 3 consecutive doubles
-		if g.DoublesCount >= 3 {
-			g.AddMessage(fmt.Sprintf("%s: 3 doubles! Go to jail!", p.Name))
-			g.sendToJail(p)
-			g.Phase = PhasePostAction
-			return
-		}
-
-		// Handle jail
-		if p.InJail {
-			if g.Doubles {
-				p.InJail = false
-				p.JailTurns = 0
-				g.AddMessage(fmt.Sprintf("%s rolled doubles and is free!", p.Name))
-			} else {
-				p.JailTurns++
-				if p.JailTurns >= config.MaxJailTurns {
-					p.Pay(config.JailFine)
-					p.InJail = false
-					p.JailTurns = 0
-					g.AddMessage(fmt.Sprintf("%s paid %d MAD jail fine (forced)", p.Name, config.JailFine))
-				} else {
-					g.AddMessage(fmt.Sprintf("%s stays in jail (%d/3 turns)", p.Name, p.JailTurns))
-					g.Phase = PhasePostAction
-					return
-				}
-			}
-		}
-
-		// Start movement
-		g.startMove(p.Position, total)
+		return
 	}
-}
-
-// startMove initiates token movement animation.
-func (g *Game) startMove(from, steps int) {
-	g.Phase = PhaseMoving
-	g.MoveFrom = from
-	g.MoveSteps = steps
-	g.MoveCurrent = 0
-	g.MoveTimer = 0
-}
-
-// updateTokenMove advances the token movement animation.
-func (g *Game) updateTokenMove(dt float64) {
-	g.MoveTimer += dt
-	if g.MoveTimer >= config.TokenMoveDuration {
-		g.MoveTimer -= config.TokenMoveDuration
-		g.MoveCurrent++
-
-		p := g.currentPlayer()
-		prevPos := (g.MoveFrom + g.MoveCurrent - 1) % config.SpaceCount
-		newPos := (g.MoveFrom + g.MoveCurrent) % config.SpaceCount
-		p.Position = newPos
-
-		// Check for passing GO (crossed from 39 to 0)
-		if newPos < prevPos && g.MoveCurrent < g.MoveSteps {
-			p.Receive(config.GoSalary)
-			g.AddMessage(fmt.Sprintf("%s passed GO! +%d MAD", p.Name, config.GoSalary))
-			g.Audio.PlayPassGo()
-		}
-
-		if g.MoveCurrent >= g.MoveSteps {
-			g.MoveTo = newPos
-
-			// Check if passed GO on final step
-			if newPos < prevPos {
-				p.Receive(config.GoSalary)
-				g.AddMessage(fmt.Sprintf("%s passed GO! +%d MAD", p.Name, config.GoSalary))
-				g.Audio.PlayPassGo()
-			}
-
-			g.Phase = PhaseLanded
-		}
+	if g.Phase != engine.PhaseAuction {
+		g.Viewer = g.Actor()
 	}
-}
-
-// resolveLanding handles what happens when a player lands on a space.
-func (g *Game) resolveLanding() {
-	p := g.currentPlayer()
-	space := g.Board.Spaces[p.Position]
-	g.AddMessage(fmt.Sprintf("%s landed on %s", p.Name, space.Name))
-
-	switch space.Type {
-	case board.SpaceGo:
-		// Already collected when passing
-		g.Phase = PhasePostAction
-
-	case board.SpaceProperty, board.SpaceRailroad, board.SpaceUtility:
-		prop := g.Board.Properties[p.Position]
-		if prop.OwnerID < 0 {
-			// Unowned — offer to buy
-			g.Dialog = DialogBuyProperty
-			g.Phase = PhaseDialog
-			g.AddMessage(fmt.Sprintf("Buy %s for %d MAD?", space.Name, space.Price))
-			g.updateButtonStates()
-		} else if prop.OwnerID != p.ID {
-			// Owned by someone else — pay rent
-			rent := g.calculateRent(p.Position)
-			if prop.Mortgaged {
-				g.AddMessage(fmt.Sprintf("%s is mortgaged - no rent", space.Name))
-			} else {
-				owner := g.Players[prop.OwnerID]
-				g.AddMessage(fmt.Sprintf("%s pays %d MAD rent to %s", p.Name, rent, owner.Name))
-				g.Audio.PlayRent()
-				g.payDebt(p, owner, rent)
-			}
-			g.Phase = PhasePostAction
-		} else {
-			// Own property
-			g.Phase = PhasePostAction
-		}
 
-	case board.SpaceChance:
-		g.drawChanceCard()
-
-	case board.SpaceCommunityChest:
-		g.drawCommunityCard()
+	g.updateButtonStates()
 
-	case board.SpaceTax:
-		if p.Position == 4 {
-			// Income Tax: player chooses between flat 200 MAD or 10% of net worth
-			g.Dialog = DialogIncomeTax
-			g.Phase = PhaseDialog
+	// Handle clicks: an open dialog takes the click before the panel buttons
+	if g.MouseClicked && !g.awaitingHost {
+		if g.Dialog != DialogNone && g.DialogHovered != render.DialogNoHover {
+			g.handleDialogClicks()
 		} else {
-			// Luxury Tax or other flat taxes
-			g.AddMessage(fmt.Sprintf("%s pays %d MAD tax", p.Name, space.TaxAmount))
-			g.payDebt(p, nil, space.TaxAmount) // nil = bank
-			g.Phase = PhasePostAction
+			g.handleButtonClicks()
 		}
-
-	case board.SpaceJail:
-		g.AddMessage(fmt.Sprintf("%s is just visiting", p.Name))
-		g.Phase = PhasePostAction
-
-	case board.SpaceFreeParking:
-		g.AddMessage("Free parking - nothing happens")
-		g.Phase = PhasePostAction
-
-	case board.SpaceGoToJail:
-		g.AddMessage(fmt.Sprintf("%s goes to jail!", p.Name))
-		g.sendToJail(p)
-		g.Phase = PhasePostAction
 	}
 }
 
-// buyProperty handles the player buying the current property.
-func (g *Game) buyProperty() {
-	p := g.currentPlayer()
-	space := g.Board.Spaces[p.Position]
-
-	if p.Money < space.Price {
-		g.AddMessage("Not enough money!")
-		return
+// perform makes a move for the player the engine is waiting on. Mouse
+// clicks and the AI both come through here; it returns false if the engine
+// rejected the action. A LAN client sends the move to the host instead.
+func (g *Game) perform(a engine.Action) bool {
+	if g.Client != nil {
+		return g.sendAction(a)
 	}
-
-	p.Pay(space.Price)
-	p.AddProperty(p.Position)
-	g.Board.Properties[p.Position].OwnerID = p.ID
-	g.AddMessage(fmt.Sprintf("%s bought %s for %d MAD", p.Name, space.Name, space.Price))
-	g.Audio.PlayPurchase()
-	g.Dialog = DialogNone
-	g.Phase = PhasePostAction
-	g.updateButtonStates()
-}
-
-// declineBuy handles the player declining to buy — triggers an auction.
-func (g *Game) declineBuy() {
-	p := g.currentPlayer()
-	g.AddMessage(fmt.Sprintf("%s declined to buy", p.Name))
-	g.Dialog = DialogNone
-	g.startAuction(p.Position)
+	return g.apply(g.Actor(), a)
 }
 
-// sendToJail moves a player to jail.
-func (g *Game) sendToJail(p *player.Player) {
-	p.Position = config.JailPosition
-	p.InJail = true
-	p.JailTurns = 0
-	g.Audio.PlayJail()
-}
+// apply applies player id's action, relays it to any clients, then starts
+// the animation and dialog it leads to.
+func (g *Game) apply(id int, a engine.Action) bool {
+	p := g.CurrentPlayer()
+	from, wasJailed := p.Position, p.InJail
 
-// endTurn finishes the current turn and advances to the next player.
-func (g *Game) endTurn() {
-	// Check if doubles — roll again
-	if g.Doubles && !g.currentPlayer().InJail {
-		g.Phase = PhasePreRoll
-		g.AddMessage(fmt.Sprintf("%s rolls again (doubles)", g.currentPlayer().Name))
-		g.Die1 = 0
-		g.Die2 = 0
-		g.updateButtonStates()
-		return
+	g.applied = nil
+	if err := g.Apply(id, a); err != nil {
+		return false
+	}
+	g.moves++
+	if g.Host != nil {
+		g.relay(id, a, g.applied)
 	}
 
-	g.nextPlayer()
-	g.Phase = PhasePreRoll
-	g.Die1 = 0
-	g.Die2 = 0
-	g.Doubles = false
-	g.AddMessage(fmt.Sprintf("--- %s's turn ---", g.currentPlayer().Name))
+	if _, ok := a.(engine.RollDice); ok {
+		g.startDiceAnim(p, from, wasJailed)
+	}
+	g.syncDialog()
 	g.updateButtonStates()
 
-	// Check win condition
-	if len(g.alivePlayers()) <= 1 {
+	if g.IsOver() {
 		g.State = StateGameOver
+	} else if g.autosaveDue && g.Client == nil {
+		g.autosaveDue = false
+		g.autosave()
 	}
+	return true
 }
 
-// calculateRent computes rent for a property.
-func (g *Game) calculateRent(spaceIndex int) int {
-	space := g.Board.Spaces[spaceIndex]
-	prop := g.Board.Properties[spaceIndex]
-
-	if prop.Mortgaged {
-		return 0
-	}
-
-	switch space.Type {
-	case board.SpaceProperty:
-		houses := prop.Houses
-		if houses > 0 {
-			return space.Rent[houses]
-		}
-		// Check for monopoly (doubles base rent)
-		if g.hasMonopoly(prop.OwnerID, space.Group) {
-			return space.Rent[0] * 2
-		}
-		return space.Rent[0]
-
-	case board.SpaceRailroad:
-		count := g.countOwnedRailroads(prop.OwnerID)
-		rents := [4]int{25, 50, 100, 200}
-		if count >= 1 && count <= 4 {
-			return rents[count-1]
+// syncDialog shows the dialog for the decision the engine is waiting on,
+// leaving build/mortgage/trade dialogs opened from the panel alone.
+func (g *Game) syncDialog() {
+	switch g.Phase {
+	case engine.PhaseJailDecision:
+		g.Dialog = DialogJailOptions
+	case engine.PhaseBuyDecision:
+		g.Dialog = DialogBuyProperty
+	case engine.PhaseIncomeTax:
+		g.Dialog = DialogIncomeTax
+	case engine.PhaseAuction:
+		g.Dialog = DialogAuction
+	case engine.PhaseTradeResponse:
+		g.Dialog = DialogTradeReceived
+	case engine.PhaseTransfer:
+		g.Dialog = DialogMortgagedTransfer
+	case engine.PhaseDebt:
+		switch g.Dialog {
+		case DialogBuild, DialogMortgage, DialogTrade:
+		default:
+			g.Dialog = DialogBankruptcy
 		}
-		return 25
-
-	case board.SpaceUtility:
-		count := g.countOwnedUtilities(prop.OwnerID)
-		diceTotal := g.Die1 + g.Die2
-		if count == 2 {
-			return diceTotal * 10
+	default:
+		switch g.Dialog {
+		case DialogBuild, DialogMortgage, DialogTrade:
+		default:
+			g.Dialog = DialogNone
 		}
-		return diceTotal * 4
 	}
+}
 
-	return 0
+// panelButton is a side-panel button: either a decision sent straight to
+// the engine, or a dialog to open when the player has a choice to make.
+type panelButton struct {
+	Label  string
+	Action engine.Action
+	Open   func()
+	Offers func(engine.Action) bool // for Open: which legal actions the dialog offers
 }
 
-// hasMonopoly checks if a player owns all properties in a colour group.
-func (g *Game) hasMonopoly(playerID int, group board.ColorGroup) bool {
-	if group == board.GroupNone {
-		return false
-	}
-	spaces := g.Board.SpacesInGroup(group)
-	for _, idx := range spaces {
-		if g.Board.Properties[idx].OwnerID != playerID {
+// panelButtons lists the side-panel buttons in layout order.
+func (g *Game) panelButtons() []panelButton {
+	decline := "Auction"
+	if g.Rules.NoAuctions {
+		decline = "Decline"
+	}
+	return []panelButton{
+		{Label: "Roll Dice", Action: engine.RollDice{}},
+		{Label: "Buy", Action: engine.BuyProperty{}},
+		{Label: decline, Action: engine.DeclineBuy{}},
+		{Label: "Build", Open: g.openBuildDialog, Offers: func(a engine.Action) bool {
+			switch a.(type) {
+			case engine.Build, engine.SellHouse:
+				return true
+			}
 			return false
-		}
+		}},
+		{Label: "Mortgage", Open: g.openMortgageDialog, Offers: func(a engine.Action) bool {
+			switch a.(type) {
+			case engine.Mortgage, engine.Unmortgage:
+				return true
+			}
+			return false
+		}},
+		{Label: "Trade", Open: g.openTradeDialog, Offers: func(a engine.Action) bool {
+			_, ok := a.(engine.ProposeTrade)
+			return ok
+		}},
+		{Label: "End Turn", Action: engine.EndTurn{}},
 	}
-	return true
 }
 
-func (g *Game) countOwnedRailroads(playerID int) int {
-	count := 0
-	for _, idx := range g.Board.RailroadSpaces() {
-		if g.Board.Properties[idx].OwnerID == playerID {
-			count++
+// handleButtonClicks processes clicks on the action buttons.
+func (g *Game) handleButtonClicks() {
+	for i, pb := range g.panelButtons() {
+		if i >= len(g.Buttons) {
+			break
 		}
-	}
-	return count
-}
-
-func (g *Game) countOwnedUtilities(playerID int) int {
-	count := 0
-	for _, idx := range g.Board.UtilitySpaces() {
-		if g.Board.Properties[idx].OwnerID == playerID {
-			count++
+		btn := g.Buttons[i]
+		if !btn.Visible || !btn.Enabled || !btn.Contains(g.MouseX, g.MouseY) {
+			continue
+		}
+		if pb.Action != nil {
+			g.perform(pb.Action)
+		} else {
+			pb.Open()
 		}
-	}
-	return count
-}
-
-// updateButtonStates enables/disables buttons based on current phase.
-func (g *Game) updateButtonStates() {
-	if len(g.Buttons) < 7 {
 		return
 	}
-
-	// Roll Dice
-	g.Buttons[0].Enabled = g.Phase == PhasePreRoll
-	// Buy
-	g.Buttons[1].Enabled = g.Phase == PhaseDialog && g.Dialog == DialogBuyProperty
-	g.Buttons[1].Visible = g.Phase == PhaseDialog && g.Dialog == DialogBuyProperty
-	// Auction
-	g.Buttons[2].Enabled = g.Phase == PhaseDialog && g.Dialog == DialogBuyProperty
-	g.Buttons[2].Visible = g.Phase == PhaseDialog && g.Dialog == DialogBuyProperty
-	// Build
-	g.Buttons[3].Enabled = g.Phase == PhasePreRoll || g.Phase == PhasePostAction
-	// Mortgage
-	g.Buttons[4].Enabled = g.Phase == PhasePreRoll || g.Phase == PhasePostAction
-	// Trade
-	g.Buttons[5].Enabled = g.Phase == PhasePreRoll || g.Phase == PhasePostAction
-	// End Turn
-	g.Buttons[6].Enabled = g.Phase == PhasePostAction
 }
 
-// drawChanceCard draws and executes a Chance card.
-func (g *Game) drawChanceCard() {
-	card := g.Board.ChanceDeck.Draw()
-	g.Audio.PlayCardDraw()
-	g.AddMessage("Chance: " + card.Text)
-	g.executeCard(card)
+// closeDialog dismisses a panel dialog (build, mortgage, trade).
+func (g *Game) closeDialog() {
+	g.Dialog = DialogNone
+	g.syncDialog()
+	g.updateButtonStates()
 }
 
-// drawCommunityCard draws and executes a Community Chest card.
-func (g *Game) drawCommunityCard() {
-	card := g.Board.CommunityDeck.Draw()
-	g.Audio.PlayCardDraw()
-	g.AddMessage("Caisse: " + card.Text)
-	g.executeCard(card)
+// resetAnimation clears any dice or token animation in progress.
+func (g *Game) resetAnimation() {
+	g.DiceRolling = false
+	g.DiceAnimTimer = 0
+	g.MoveSteps = 0
+	g.MoveCurrent = 0
+	g.MoveTimer = 0
+	g.AITimer = 0
 }
 
-// executeCard applies a card's effect.
-func (g *Game) executeCard(card board.Card) {
-	p := g.currentPlayer()
-
-	switch card.Effect {
-	case board.EffectCollect:
-		p.Receive(card.Amount)
-		g.AddMessage(fmt.Sprintf("%s receives %d MAD", p.Name, card.Amount))
-
-	case board.EffectPay:
-		p.Pay(card.Amount)
-		g.AddMessage(fmt.Sprintf("%s pays %d MAD", p.Name, card.Amount))
-
-	case board.EffectMoveTo:
-		target := card.Amount
-		// Check if passing GO
-		if target < p.Position && target != 0 {
-			p.Receive(config.GoSalary)
-			g.AddMessage(fmt.Sprintf("%s passed GO! +%d MAD", p.Name, config.GoSalary))
-		} else if target == 0 {
-			p.Receive(config.GoSalary)
-			g.AddMessage(fmt.Sprintf("%s collects %d MAD from DEPART", p.Name, config.GoSalary))
-		}
-		p.Position = target
-		g.Phase = PhaseLanded
-		return
-
-	case board.EffectMoveSteps:
-		steps := card.Amount
-		newPos := (p.Position + steps + config.SpaceCount) % config.SpaceCount
-		p.Position = newPos
-		g.Phase = PhaseLanded
-		return
-
-	case board.EffectGoToJail:
-		g.sendToJail(p)
-
-	case board.EffectGetOutOfJail:
-		p.GetOutOfJailCards++
-		g.AddMessage(fmt.Sprintf("%s gets a Get Out of Jail Free card!", p.Name))
-
-	case board.EffectPayPerHouse:
-		totalHouses := 0
-		totalHotels := 0
-		for _, idx := range p.Properties {
-			h := g.Board.Properties[idx].Houses
-			if h == 5 {
-				totalHotels++
-			} else {
-				totalHouses += h
-			}
-		}
-		cost := totalHouses*card.Amount + totalHotels*card.AmountHotel
-		p.Pay(cost)
-		g.AddMessage(fmt.Sprintf("%s pays %d MAD (%d houses, %d hotels)", p.Name, cost, totalHouses, totalHotels))
-
-	case board.EffectCollectAll:
-		total := 0
-		for _, other := range g.Players {
-			if other.ID != p.ID && !other.Bankrupt {
-				other.Pay(card.Amount)
-				total += card.Amount
-			}
-		}
-		p.Receive(total)
-		g.AddMessage(fmt.Sprintf("%s collects %d MAD from all players", p.Name, total))
+// startDiceAnim starts the dice animation for a roll the engine has already
+// resolved, followed by the token walking the rolled number of spaces.
+func (g *Game) startDiceAnim(p *player.Player, from int, wasJailed bool) {
+	g.DiceRolling = true
+	g.DiceAnimTimer = 0
+	g.Audio.PlayDiceRoll()
 
-	case board.EffectPayAll:
-		for _, other := range g.Players {
-			if other.ID != p.ID && !other.Bankrupt {
-				p.Pay(card.Amount)
-				other.Receive(card.Amount)
-			}
-		}
-		total := card.Amount * (len(g.alivePlayers()) - 1)
-		g.AddMessage(fmt.Sprintf("%s pays %d MAD total to all players", p.Name, total))
+	g.MovePlayer = p.ID
+	g.MoveFrom = from
+	g.MoveCurrent = 0
+	g.MoveTimer = 0
+	g.MoveSteps = g.Die1 + g.Die2
 
-	case board.EffectMoveNearest:
-		// Amount == 1: nearest railroad, Amount == 2: nearest utility
-		var targets []int
-		if card.Amount == 1 {
-			targets = g.Board.RailroadSpaces()
-		} else {
-			targets = g.Board.UtilitySpaces()
-		}
-		nearest := g.findNearestClockwise(p.Position, targets)
-		if nearest >= 0 {
-			// Check if passing GO
-			if nearest < p.Position {
-				p.Receive(config.GoSalary)
-				g.AddMessage(fmt.Sprintf("%s passed GO! +%d MAD", p.Name, config.GoSalary))
-			}
-			p.Position = nearest
-			g.Phase = PhaseLanded
-			return
-		}
+	// No walk if the roll sent the player to jail (3 doubles) or kept them
+	// there; a jailed player who stays has their jail turn count raised.
+	if g.DoublesCount >= 3 || (wasJailed && p.InJail && p.JailTurns > 0) {
+		g.MoveSteps = 0
 	}
+}
 
-	g.Phase = PhasePostAction
+// animating reports whether the dice or a token are still moving.
+func (g *Game) animating() bool {
+	return g.DiceRolling || g.MoveCurrent < g.MoveSteps
 }
 
-// payDebt attempts to pay a debt. If unable, triggers bankruptcy.
-// creditor is nil when paying the bank.
-func (g *Game) payDebt(debtor *player.Player, creditor *player.Player, amount int) {
-	if debtor.Money >= amount {
-		debtor.Pay(amount)
-		if creditor != nil {
-			creditor.Receive(amount)
+// updateAnimation advances the dice animation, then the token walk.
+func (g *Game) updateAnimation(dt float64) {
+	if g.DiceRolling {
+		g.DiceAnimTimer += dt
+		if g.DiceAnimTimer >= config.DiceAnimDuration {
+			g.DiceRolling = false
 		}
 		return
 	}
 
-	// Try to auto-liquidate: sell houses, then mortgage
-	g.autoLiquidate(debtor)
-
-	if debtor.Money >= amount {
-		debtor.Pay(amount)
-		if creditor != nil {
-			creditor.Receive(amount)
-		}
-		return
+	g.MoveTimer += dt
+	if g.MoveTimer >= config.TokenMoveDuration {
+		g.MoveTimer -= config.TokenMoveDuration
+		g.MoveCurrent++
 	}
-
-	// Bankrupt
-	g.declareBankruptcy(debtor, creditor)
 }
 
-// autoLiquidate sells houses and mortgages properties to raise cash.
-func (g *Game) autoLiquidate(p *player.Player) {
-	// First: sell all houses/hotels
-	for {
-		sold := false
-		for _, idx := range p.Properties {
-			if g.Board.Properties[idx].Houses > 0 && g.CanSellHouseOnSpace(idx) {
-				refund := g.SellHouse(idx)
-				p.Receive(refund)
-				space := g.Board.Spaces[idx]
-				g.AddMessage(fmt.Sprintf("%s sold house on %s (+%d MAD)", p.Name, space.Name, refund))
-				sold = true
-			}
-		}
-		if !sold {
-			break
-		}
+// tokenPosition returns the space a player's token is drawn on, which lags
+// behind the engine while the token walks.
+func (g *Game) tokenPosition(p *player.Player) int {
+	if g.animating() && p.ID == g.MovePlayer {
+		return (g.MoveFrom + g.MoveCurrent) % config.SpaceCount
 	}
+	return p.Position
+}
 
-	// Then: mortgage unimproved properties
-	for _, idx := range p.Properties {
-		prop := g.Board.Properties[idx]
-		if !prop.Mortgaged && prop.Houses == 0 {
-			val := g.MortgageProperty(idx)
-			p.Receive(val)
-			space := g.Board.Spaces[idx]
-			g.AddMessage(fmt.Sprintf("%s mortgaged %s (+%d MAD)", p.Name, space.Name, val))
-		}
+// updateButtonStates enables each panel button only if the engine would
+// accept what it does from the human it is waiting on.
+func (g *Game) updateButtonStates() {
+	buttons := g.panelButtons()
+	if len(g.Buttons) < len(buttons) {
+		return
 	}
-}
 
-// declareBankruptcy eliminates a player and transfers assets.
-func (g *Game) declareBankruptcy(debtor *player.Player, creditor *player.Player) {
-	g.AddMessage(fmt.Sprintf("%s is BANKRUPT!", debtor.Name))
-	g.Audio.PlayBankruptcy()
-	debtor.Bankrupt = true
+	idle := !g.animating() && !g.Players[g.Actor()].IsAI && g.controls(g.Actor()) && !g.awaitingHost &&
+		g.Dialog != DialogBuild && g.Dialog != DialogMortgage && g.Dialog != DialogTrade
+	var legal []engine.Action
+	if idle {
+		legal = g.LegalActions(g.Actor())
+	}
 
-	if creditor != nil {
-		// Transfer all assets to creditor
-		creditor.Receive(debtor.Money)
-		for _, idx := range debtor.Properties {
-			creditor.AddProperty(idx)
-			g.Board.Properties[idx].OwnerID = creditor.ID
-		}
-		creditor.GetOutOfJailCards += debtor.GetOutOfJailCards
-		g.AddMessage(fmt.Sprintf("%s receives all of %s's assets", creditor.Name, debtor.Name))
-	} else {
-		// Owed to bank — return properties to bank (unowned), auction them
-		for _, idx := range debtor.Properties {
-			g.Board.Properties[idx].OwnerID = -1
-			g.Board.Properties[idx].Mortgaged = false
-			g.Board.Properties[idx].Houses = 0
+	for i, pb := range buttons {
+		enabled := false
+		for _, a := range legal {
+			if (pb.Action != nil && a == pb.Action) || (pb.Offers != nil && pb.Offers(a)) {
+				enabled = true
+				break
+			}
 		}
+		g.Buttons[i].Enabled = enabled
 	}
 
-	debtor.Money = 0
-	debtor.Properties = nil
-	debtor.GetOutOfJailCards = 0
-
-	// Check win condition
-	alive := g.alivePlayers()
-	if len(alive) <= 1 {
-		g.State = StateGameOver
-	}
+	// Buy and Auction only appear while a purchase is being decided
+	buying := g.Phase == engine.PhaseBuyDecision
+	g.Buttons[1].Visible = buying
+	g.Buttons[2].Visible = buying
 }
 
 // openBuildDialog opens the build house dialog.
 func (g *Game) openBuildDialog() {
-	p := g.currentPlayer()
-	buildable := g.BuildableProperties(p.ID)
-	if len(buildable) == 0 {
+	if len(g.buildOptions()) == 0 {
 		g.AddMessage("No properties available to build on")
 		return
 	}
-	g.SelectableSpaces = buildable
-	g.SelectedSpace = -1
 	g.Dialog = DialogBuild
-	g.Phase = PhaseDialog
 }
 
 // openMortgageDialog opens the mortgage dialog.
 func (g *Game) openMortgageDialog() {
-	p := g.currentPlayer()
-	mortgageable := g.MortgageableProperties(p.ID)
-	unmortgageable := g.UnmortgageableProperties(p.ID)
-	all := append(mortgageable, unmortgageable...)
-	if len(all) == 0 {
+	if len(g.mortgageOptions()) == 0 {
 		g.AddMessage("No properties available to mortgage/unmortgage")
 		return
 	}
-	g.SelectableSpaces = all
-	g.SelectedSpace = -1
 	g.Dialog = DialogMortgage
-	g.Phase = PhaseDialog
 }
 
-// updateAI handles AI player turns with automatic actions.
+// updateAI makes the decision the engine is waiting on for an AI player.
+// The decision is worked out on a copy of the game in the background, so a
+// hard player weighing its options does not hold up the frame.
 func (g *Game) updateAI(dt float64) {
-	p := g.currentPlayer()
+	if g.thinking != nil {
+		g.finishThinking()
+		return
+	}
 
-	// Add delay for AI actions so humans can follow
-	if g.Phase == PhasePreRoll || g.Phase == PhasePostAction || g.Phase == PhaseJailDecision || g.Phase == PhaseDialog {
+	// Auctions and trade answers resolve immediately; otherwise add a
+	// delay so humans can follow
+	if g.Phase != engine.PhaseAuction && g.Phase != engine.PhaseTradeResponse {
 		g.AITimer += dt
 		if g.AITimer < 0.5 {
 			return
 		}
 		g.AITimer = 0
 	}
-
-	switch g.Phase {
-	case PhasePreRoll:
-		// AI tries to build before rolling
-		g.aiBuildIfPossible()
-
-		if p.InJail {
-			g.Phase = PhaseJailDecision
-			g.Dialog = DialogJailOptions
-		} else {
-			g.startDiceRoll()
-		}
-	case PhaseJailDecision:
-		// Determine game stage: late game if >20 total properties owned
-		totalProps := 0
-		for _, pl := range g.Players {
-			totalProps += len(pl.Properties)
-		}
-		lateGame := totalProps > 20
-
-		if p.GetOutOfJailCards > 0 && !lateGame {
-			p.GetOutOfJailCards--
-			p.InJail = false
-			p.JailTurns = 0
-			g.Dialog = DialogNone
-			g.Phase = PhasePreRoll
-			g.AddMessage(fmt.Sprintf("%s (AI) used Get Out of Jail Free card", p.Name))
-		} else if lateGame {
-			// Late game: prefer staying in jail (safe from rent)
-			// Unless forced out after max turns
-			g.Dialog = DialogNone
-			g.startDiceRoll()
-		} else if p.Money >= config.JailFine+200 {
-			p.Pay(config.JailFine)
-			p.InJail = false
-			p.JailTurns = 0
-			g.Dialog = DialogNone
-			g.Phase = PhasePreRoll
-			g.AddMessage(fmt.Sprintf("%s (AI) paid %d MAD jail fine", p.Name, config.JailFine))
-		} else {
-			g.Dialog = DialogNone
-			g.startDiceRoll()
-		}
-	case PhaseRolling:
-		g.updateDiceAnim(dt)
-	case PhaseMoving:
-		g.updateTokenMove(dt)
-	case PhaseLanded:
-		g.resolveLanding()
-	case PhaseDialog:
-		switch g.Dialog {
-		case DialogBuyProperty:
-			space := g.Board.Spaces[p.Position]
-			// Count total owned to adjust strategy
-			totalOwned := 0
-			for _, pl := range g.Players {
-				totalOwned += len(pl.Properties)
-			}
-			if p.ShouldBuy(space.Price, totalOwned) {
-				g.buyProperty()
-			} else {
-				g.declineBuy()
-			}
-		case DialogIncomeTax:
-			// AI picks the cheaper option
-			tenPercent := g.PlayerNetWorth(p.ID) / 10
-			if tenPercent < 200 {
-				g.AddMessage(fmt.Sprintf("%s (AI) pays %d MAD income tax (10%%)", p.Name, tenPercent))
-				g.payDebt(p, nil, tenPercent)
-			} else {
-				g.AddMessage(fmt.Sprintf("%s (AI) pays 200 MAD income tax (flat)", p.Name))
-				g.payDebt(p, nil, 200)
-			}
-			g.Dialog = DialogNone
-			g.Phase = PhasePostAction
-		}
-	case PhasePostAction:
-		g.endTurn()
-	case PhaseTurnEnd:
-		g.endTurn()
-	}
+	g.think()
 }
 
-// findNearestClockwise finds the nearest target space clockwise from the current position.
-func (g *Game) findNearestClockwise(from int, targets []int) int {
-	best := -1
-	bestDist := config.SpaceCount + 1
-	for _, t := range targets {
-		dist := (t - from + config.SpaceCount) % config.SpaceCount
-		if dist == 0 {
-			dist = config.SpaceCount // same space means go all the way around
-		}
-		if dist < bestDist {
-			bestDist = dist
-			best = t
-		}
-	}
-	return best
+// thought is an AI decision worked out for the game as it stood after
+// moves actions, with the state the dice were left in.
+type thought struct {
+	game    *engine.GameState
+	moves   int
+	actions []engine.Action
+	rand    uint64
 }
 
-// groupPriority returns a build priority for a colour group.
-// Lower values are higher priority (based on standard Monopoly landing frequency).
-func groupPriority(g board.ColorGroup) int {
-	switch g {
-	case board.GroupOrange:
-		return 0
-	case board.GroupRed:
-		return 1
-	case board.GroupYellow:
-		return 2
-	case board.GroupGreen:
-		return 3
-	case board.GroupLightBlue:
-		return 4
-	case board.GroupPink:
-		return 5
-	case board.GroupDarkBlue:
-		return 6
-	case board.GroupBrown:
-		return 7
-	default:
-		return 99
-	}
+// think starts working out the AI's decision on a copy of the game.
+func (g *Game) think() {
+	r := rng.New(0)
+	r.SetState(g.Rand.State())
+	c := g.Clone(r)
+	t := thought{game: g.GameState, moves: g.moves}
+	done := make(chan thought, 1)
+	g.thinking = done
+	go func() {
+		t.actions = ai.Decide(c)
+		t.rand = r.State()
+		done <- t
+	}()
 }
 
-// aiBuildIfPossible has the AI strategically build one house per call.
-// It prioritises high-traffic groups and focuses on reaching 3 houses
-// on one group before starting another (the ROI sweet spot).
-func (g *Game) aiBuildIfPossible() {
-	p := g.currentPlayer()
-	buildable := g.BuildableProperties(p.ID)
-	if len(buildable) == 0 {
+// finishThinking performs the AI's decision once it is ready. A decision
+// for a game that has since moved on is dropped and made again.
+func (g *Game) finishThinking() {
+	var t thought
+	select {
+	case t = <-g.thinking:
+	default:
 		return
 	}
-
-	// Use a lower buffer when cash-rich
-	buffer := player.AIBuildBuffer
-	if p.Money > 1000 {
-		buffer = player.AIBuildBufferLow
+	g.thinking = nil
+	if t.game != g.GameState || t.moves != g.moves {
+		return
 	}
 
-	// Sort buildable properties: prefer groups with fewer houses (to reach 3 first),
-	// then by group priority (high-frequency groups first).
-	sort.Slice(buildable, func(i, j int) bool {
-		si := g.Board.Spaces[buildable[i]]
-		sj := g.Board.Spaces[buildable[j]]
-		hi := g.Board.Properties[buildable[i]].Houses
-		hj := g.Board.Properties[buildable[j]].Houses
-
-		// Prefer the group that hasn't reached 3 houses yet
-		iUnder3 := hi < 3
-		jUnder3 := hj < 3
-		if iUnder3 != jUnder3 {
-			return iUnder3
-		}
-		// Then by group priority
-		pi := groupPriority(si.Group)
-		pj := groupPriority(sj.Group)
-		if pi != pj {
-			return pi < pj
-		}
-		// Then by fewest houses (even building)
-		return hi < hj
-	})
-
-	// Build one house on the best candidate
-	idx := buildable[0]
-	space := g.Board.Spaces[idx]
-	if p.Money >= space.HouseCost+buffer {
-		cost := g.BuildHouse(idx)
-		p.Pay(cost)
-		level := g.Board.Properties[idx].Houses
-		levelName := fmt.Sprintf("%d house(s)", level)
-		if level == config.HotelLevel {
-			levelName = "hotel"
+	// Easy's whims draw on the dice, which the game has to match
+	g.Rand.SetState(t.rand)
+	for _, a := range t.actions {
+		if !g.perform(a) {
+			return
 		}
-		g.AddMessage(fmt.Sprintf("%s (AI) built on %s (%s)", p.Name, space.Name, levelName))
 	}
 }
-
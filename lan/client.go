@@ -0,0 +1,102 @@
+package lan
+
+import (
+	"net"
+	"time"
+)
+
+// dialTimeout bounds how long joining waits for the host to answer.
+const dialTimeout = 5 * time.Second
+
+// redialDelay is the pause between attempts to reach a host again.
+const redialDelay = 2 * time.Second
+
+// Client is a connection to a host, used from the game loop.
+type Client struct {
+	Addr     string
+	Name     string
+	Seat     int    // player this client controls; -1 before the game or when watching
+	Token    string // proves the seat is ours when reconnecting
+	conn     *conn
+	inbox    chan Inbound
+	redialed chan *conn
+	stop     chan struct{}
+}
+
+// Join connects to the host at addr and asks to play under name; a
+// missing port means DefaultPort.
+func Join(addr, name string) (*Client, error) {
+	addr = withPort(addr)
+	c, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	cl := &Client{
+		Addr:     addr,
+		Name:     name,
+		Seat:     -1,
+		inbox:    make(chan Inbound, outboxSize),
+		redialed: make(chan *conn, 1),
+		stop:     make(chan struct{}),
+	}
+	cl.attach(newConn(c))
+	return cl, nil
+}
+
+// attach starts reading from cn and introduces the client on it.
+func (c *Client) attach(cn *conn) {
+	c.conn = cn
+	go cn.read(nil, c.inbox)
+	c.Send(Message{Type: MsgHello, Name: c.Name, Token: c.Token})
+}
+
+// Reconnect keeps dialling the host in the background after the
+// connection drops, then asks for the seat back. The host answers with
+// MsgResume, or refuses.
+func (c *Client) Reconnect() {
+	go func() {
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-time.After(redialDelay):
+			}
+			if nc, err := net.DialTimeout("tcp", c.Addr, dialTimeout); err == nil {
+				c.redialed <- newConn(nc)
+				return
+			}
+		}
+	}()
+}
+
+// Poll returns the next message from the host, if one is waiting. A start
+// message seats the client before it is returned.
+func (c *Client) Poll() (Inbound, bool) {
+	select {
+	case cn := <-c.redialed:
+		c.attach(cn)
+	default:
+	}
+	select {
+	case in := <-c.inbox:
+		if in.Err == nil && in.Message.Type == MsgStart {
+			c.Seat = in.Message.Seat
+			c.Token = in.Message.Token
+		}
+		return in, true
+	default:
+		return Inbound{}, false
+	}
+}
+
+// Send queues a message for the host. Messages sent while the connection
+// is down are lost.
+func (c *Client) Send(m Message) {
+	c.conn.send(m)
+}
+
+// Close disconnects from the host and stops any reconnecting.
+func (c *Client) Close() {
+	close(c.stop)
+	c.conn.close()
+}
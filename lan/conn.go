@@ -0,0 +1,102 @@
+package lan
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"sync"
+)
+
+// outboxSize is how many messages may wait on either side of a connection;
+// a client that falls this far behind is dropped.
+const outboxSize = 256
+
+// Inbound is a message received on a connection, or the error that ended
+// it. Peer is the host's record of the sender; clients leave it nil.
+type Inbound struct {
+	Peer    *Peer
+	Message Message
+	Err     error
+}
+
+// conn reads and writes messages on a TCP connection from goroutines of
+// its own, so a slow network never stalls the game loop.
+type conn struct {
+	c      net.Conn
+	out    chan Message
+	last   chan Message // a final message, written before closing
+	closed chan struct{}
+	once   sync.Once
+}
+
+// newConn starts writing to c; read must be started separately.
+func newConn(c net.Conn) *conn {
+	cn := &conn{
+		c:      c,
+		out:    make(chan Message, outboxSize),
+		last:   make(chan Message, 1),
+		closed: make(chan struct{}),
+	}
+	go cn.write()
+	return cn
+}
+
+// read delivers messages to inbox, tagged with peer, until the connection
+// ends; a final Inbound with Err set reports that.
+func (cn *conn) read(peer *Peer, inbox chan<- Inbound) {
+	dec := json.NewDecoder(bufio.NewReader(cn.c))
+	for {
+		var m Message
+		if err := dec.Decode(&m); err != nil {
+			cn.close()
+			inbox <- Inbound{Peer: peer, Err: err}
+			return
+		}
+		inbox <- Inbound{Peer: peer, Message: m}
+	}
+}
+
+func (cn *conn) write() {
+	enc := json.NewEncoder(cn.c)
+	for {
+		select {
+		case m := <-cn.out:
+			if err := enc.Encode(m); err != nil {
+				cn.close()
+				return
+			}
+		case m := <-cn.last:
+			enc.Encode(m)
+			cn.close()
+			return
+		case <-cn.closed:
+			return
+		}
+	}
+}
+
+// send queues m for writing; a connection too far behind is dropped.
+func (cn *conn) send(m Message) {
+	select {
+	case <-cn.closed:
+	case cn.out <- m:
+	default:
+		cn.close()
+	}
+}
+
+// finish writes m and then closes the connection.
+func (cn *conn) finish(m Message) {
+	select {
+	case cn.last <- m:
+	default:
+	}
+}
+
+// close shuts the connection; the reader then reports it ended.
+func (cn *conn) close() {
+	cn.once.Do(func() {
+		close(cn.closed)
+		cn.c.Close()
+	})
+}
@@ -0,0 +1,206 @@
+package lan
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/save"
+)
+
+// Host accepts clients for a game run by this instance. Apart from the
+// accept and connection goroutines, it is only used from the game loop.
+type Host struct {
+	ln    net.Listener
+	inbox chan Inbound
+	peers []*Peer // joined clients, in the order they joined
+}
+
+// Peer is a client connected to the host.
+type Peer struct {
+	Name   string
+	Seat   int    // player the client controls; -1 in the lobby or watching
+	Token  string // lets the client take its seat back after dropping out
+	Gone   bool   // the connection has closed
+	joined bool
+	conn   *conn
+}
+
+// Listen hosts on addr, e.g. ":7777" or "0.0.0.0"; a missing port means
+// DefaultPort.
+func Listen(addr string) (*Host, error) {
+	ln, err := net.Listen("tcp", withPort(addr))
+	if err != nil {
+		return nil, err
+	}
+	h := &Host{ln: ln, inbox: make(chan Inbound, outboxSize)}
+	go h.accept()
+	return h, nil
+}
+
+// withPort adds DefaultPort to an address that has none.
+func withPort(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(addr, DefaultPort)
+	}
+	return addr
+}
+
+func (h *Host) accept() {
+	for {
+		c, err := h.ln.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		p := &Peer{Seat: -1, conn: newConn(c)}
+		go p.conn.read(p, h.inbox)
+	}
+}
+
+// Addr returns the address clients connect to.
+func (h *Host) Addr() string {
+	return h.ln.Addr().String()
+}
+
+// Poll returns the next message from a client, if one is waiting. Joins
+// and disconnects are recorded before they are returned; a client that
+// takes back its seat comes back seated.
+func (h *Host) Poll() (Inbound, bool) {
+	select {
+	case in := <-h.inbox:
+		p := in.Peer
+		switch {
+		case in.Err != nil:
+			p.Gone = true
+		case in.Message.Type == MsgHello && !p.joined:
+			p.joined = true
+			p.Name = in.Message.Name
+			if p.Name == "" {
+				p.Name = "Guest"
+			}
+			h.reclaim(p, in.Message.Token)
+			h.peers = append(h.peers, p)
+		}
+		return in, true
+	default:
+		return Inbound{}, false
+	}
+}
+
+// reclaim seats p in place of the client that was given token. If that
+// client still looks connected, its connection is most likely a dead one
+// the host has yet to notice, so it is closed and unseated.
+func (h *Host) reclaim(p *Peer, token string) {
+	if token == "" {
+		return
+	}
+	for i, old := range h.peers {
+		if old.Token == token {
+			p.Name, p.Seat, p.Token = old.Name, old.Seat, old.Token
+			old.Seat, old.Token, old.Gone = -1, "", true
+			old.conn.close()
+			h.peers = append(h.peers[:i], h.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Lobby lists the connected clients in the order they joined.
+func (h *Host) Lobby() []*Peer {
+	var peers []*Peer
+	for _, p := range h.peers {
+		if !p.Gone {
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+// Start sends every connected client the opening state of a new game, as
+// Send trims it. seats gives the player each seated client controls; the
+// others watch.
+func (h *Host) Start(seats map[*Peer]int, state *save.SaveData) {
+	h.peers = h.Lobby()
+	for _, p := range h.peers {
+		p.Seat = -1
+		p.Token = ""
+		if id, ok := seats[p]; ok {
+			p.Seat = id
+			p.Token = newToken()
+		}
+		p.Send(Message{Type: MsgStart, Seat: p.Seat, Token: p.Token, State: state})
+	}
+}
+
+// Remote reports whether a client controls player id. The seat stays
+// remote if its client drops out.
+func (h *Host) Remote(id int) bool {
+	for _, p := range h.peers {
+		if p.Seat == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Connected reports whether the client controlling player id is still
+// connected.
+func (h *Host) Connected(id int) bool {
+	for _, p := range h.peers {
+		if p.Seat == id && !p.Gone {
+			return true
+		}
+	}
+	return false
+}
+
+// Release gives up player id's seat if its client has dropped out, so it
+// can no longer be taken back.
+func (h *Host) Release(id int) {
+	for i, p := range h.peers {
+		if p.Seat == id && p.Gone {
+			h.peers = append(h.peers[:i], h.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Broadcast sends m to every connected client, its state trimmed for each
+// by Send.
+func (h *Host) Broadcast(m Message) {
+	for _, p := range h.peers {
+		p.Send(m)
+	}
+}
+
+// Close stops hosting and disconnects every client.
+func (h *Host) Close() {
+	h.ln.Close()
+	for _, p := range h.peers {
+		p.conn.close()
+	}
+}
+
+// Send queues a message for the client. Any state goes without what would
+// tell the dice and cards to come.
+func (p *Peer) Send(m Message) {
+	if p.Gone {
+		return
+	}
+	if m.State != nil {
+		m.State = m.State.Public()
+	}
+	p.conn.send(m)
+}
+
+// Refuse turns the client away with a reason and disconnects it.
+func (p *Peer) Refuse(reason string) {
+	p.conn.finish(Message{Type: MsgReject, Text: reason})
+}
+
+// newToken returns a random seat token.
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,87 @@
+package lan
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+// pollHost waits for the host's next message.
+func pollHost(t *testing.T, h *Host) Inbound {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if in, ok := h.Poll(); ok {
+			return in
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("no message reached the host")
+	return Inbound{}
+}
+
+// pollClient waits for the client's next message.
+func pollClient(t *testing.T, c *Client) Inbound {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if in, ok := c.Poll(); ok {
+			return in
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("no message reached the client")
+	return Inbound{}
+}
+
+func TestReclaimLiveSeat(t *testing.T) {
+	h, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	c, err := Join(h.Addr(), "Amina")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	first := pollHost(t, h).Peer
+	h.Start(map[*Peer]int{first: 2}, nil)
+	if in := pollClient(t, c); in.Message.Type != MsgStart || c.Token == "" {
+		t.Fatalf("client got %+v, want a start with a token", in.Message)
+	}
+
+	// The same client comes back on a new connection before the host has
+	// seen the old one drop
+	nc, err := net.Dial("tcp", h.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+	if err := json.NewEncoder(nc).Encode(Message{Type: MsgHello, Name: "Amina", Token: c.Token}); err != nil {
+		t.Fatal(err)
+	}
+	in := pollHost(t, h)
+	if in.Peer == first || in.Peer.Seat != 2 || in.Peer.Token != c.Token {
+		t.Fatalf("new connection seated at %d, want it to take seat 2", in.Peer.Seat)
+	}
+	if lobby := h.Lobby(); len(lobby) != 1 || lobby[0] != in.Peer {
+		t.Fatalf("lobby has %d clients, want only the new connection", len(lobby))
+	}
+	if !h.Connected(2) {
+		t.Fatal("seat 2 is not connected")
+	}
+
+	// The old connection is closed, and its end no longer touches the seat
+	if in := pollClient(t, c); in.Err == nil {
+		t.Fatalf("old client got %+v, want its connection closed", in.Message)
+	}
+	if in := pollHost(t, h); in.Peer != first || in.Err == nil || first.Seat != -1 {
+		t.Fatalf("host got %+v from the old connection, want its unseated end", in)
+	}
+	if !h.Connected(2) {
+		t.Fatal("seat 2 dropped with the old connection")
+	}
+}
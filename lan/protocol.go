@@ -0,0 +1,159 @@
+// Package lan plays one game from several machines on a local network. One
+// instance hosts: it runs the engine, takes the others' connections over
+// TCP and relays every action it applies, with the events it led to and
+// the state that results. The others join as clients, send the actions of
+// the player they control and follow what the host relays, never running
+// the engine themselves: the state they are sent leaves out the dice and
+// cards to come, and other players' cash when it is hidden. A client that
+// drops out can reconnect and take its seat back with the token it was
+// given at the start.
+//
+// Messages are JSON objects, one per line.
+package lan
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/save"
+)
+
+// DefaultPort is where games are hosted when the address names no port.
+const DefaultPort = "7777"
+
+// Message types.
+const (
+	MsgHello   = "hello"   // client to host: join under Name, or take back the seat of Token
+	MsgStart   = "start"   // host to client: the game began; play Seat from State, reclaiming it with Token
+	MsgResume  = "resume"  // host to client: the seat was taken back; carry on from State
+	MsgAction  = "action"  // client to host: Action for the client's seat
+	MsgApplied = "applied" // host to client: Player's Action was applied, leading to Events and State
+	MsgState   = "state"   // host to client: the game changed outside the rules; take State
+	MsgReject  = "reject"  // host to client: a join or action was refused, see Text
+)
+
+// Message is everything sent over a connection; Type says which fields
+// are used.
+type Message struct {
+	Type   string         `json:"type"`
+	Name   string         `json:"name,omitempty"`
+	Token  string         `json:"token,omitempty"`
+	Seat   int            `json:"seat"`
+	Player int            `json:"player"`
+	Action *WireAction    `json:"action,omitempty"`
+	Events []*WireEvent   `json:"events,omitempty"`
+	State  *save.SaveData `json:"state,omitempty"`
+	Text   string         `json:"text,omitempty"`
+}
+
+// WireAction is an engine action tagged with its type name.
+type WireAction struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// WireEvent is an engine event tagged with its type name.
+type WireEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// actionTypes maps the name of every engine action to its type.
+var actionTypes = map[string]reflect.Type{}
+
+// eventTypes maps the name of every engine event to its type.
+var eventTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, a := range []engine.Action{
+		engine.RollDice{}, engine.BuyProperty{}, engine.DeclineBuy{},
+		engine.PayJailFine{}, engine.UseJailCard{}, engine.PayIncomeTax{},
+		engine.Build{}, engine.SellHouse{}, engine.Mortgage{}, engine.Unmortgage{},
+		engine.Bid{}, engine.Pass{}, engine.ProposeTrade{}, engine.AcceptTrade{},
+		engine.DeclineTrade{}, engine.EndTurn{}, engine.PayDebt{},
+		engine.DeclareBankruptcy{}, engine.ReceiveMortgaged{},
+	} {
+		t := reflect.TypeOf(a)
+		actionTypes[t.Name()] = t
+	}
+	for _, e := range []engine.Event{
+		engine.TurnStarted{}, engine.DiceRolled{}, engine.RollAgain{},
+		engine.PassedGo{}, engine.Landed{}, engine.PurchaseOffered{},
+		engine.PropertyBought{}, engine.PurchaseDeclined{}, engine.RentPaid{},
+		engine.RentWaived{}, engine.TaxPaid{}, engine.CardDrawn{},
+		engine.Collected{}, engine.Paid{}, engine.RepairsPaid{},
+		engine.JailCardReceived{}, engine.WentToJail{}, engine.LeftJail{},
+		engine.StayedInJail{}, engine.HouseBuilt{}, engine.HouseSold{},
+		engine.Mortgaged{}, engine.Unmortgaged{}, engine.AuctionStarted{},
+		engine.BidPlaced{}, engine.AuctionPassed{}, engine.AuctionWon{},
+		engine.AuctionUnsold{}, engine.TradeProposed{}, engine.TradeDeclined{},
+		engine.TradeExecuted{}, engine.InterestPaid{}, engine.DebtIncurred{},
+		engine.DebtPaid{}, engine.JackpotWon{}, engine.Bankrupt{},
+	} {
+		t := reflect.TypeOf(e)
+		eventTypes[t.Name()] = t
+	}
+}
+
+// EncodeAction tags an action for sending.
+func EncodeAction(a engine.Action) (*WireAction, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return &WireAction{Type: reflect.TypeOf(a).Name(), Data: data}, nil
+}
+
+// DecodeAction rebuilds an action sent by EncodeAction.
+func DecodeAction(w *WireAction) (engine.Action, error) {
+	if w == nil {
+		return nil, fmt.Errorf("missing action")
+	}
+	t, ok := actionTypes[w.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown action %q", w.Type)
+	}
+	v := reflect.New(t)
+	if len(w.Data) == 0 {
+		return v.Elem().Interface().(engine.Action), nil // no fields given
+	}
+	if err := json.Unmarshal(w.Data, v.Interface()); err != nil {
+		return nil, fmt.Errorf("action %s: %w", w.Type, err)
+	}
+	return v.Elem().Interface().(engine.Action), nil
+}
+
+// EncodeEvents tags events for sending.
+func EncodeEvents(events []engine.Event) ([]*WireEvent, error) {
+	var wire []*WireEvent
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		wire = append(wire, &WireEvent{Type: reflect.TypeOf(e).Name(), Data: data})
+	}
+	return wire, nil
+}
+
+// DecodeEvents rebuilds events sent by EncodeEvents.
+func DecodeEvents(wire []*WireEvent) ([]engine.Event, error) {
+	var events []engine.Event
+	for _, w := range wire {
+		if w == nil {
+			return nil, fmt.Errorf("missing event")
+		}
+		t, ok := eventTypes[w.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown event %q", w.Type)
+		}
+		v := reflect.New(t)
+		if err := json.Unmarshal(w.Data, v.Interface()); err != nil {
+			return nil, fmt.Errorf("event %s: %w", w.Type, err)
+		}
+		events = append(events, v.Elem().Interface().(engine.Event))
+	}
+	return events, nil
+}
@@ -0,0 +1,94 @@
+package lan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+)
+
+func TestActionRoundTrip(t *testing.T) {
+	actions := []engine.Action{
+		engine.RollDice{}, engine.BuyProperty{}, engine.DeclineBuy{},
+		engine.PayJailFine{}, engine.UseJailCard{},
+		engine.PayIncomeTax{}, engine.PayIncomeTax{Percent: true},
+		engine.Build{Space: 39}, engine.SellHouse{Space: 37}, engine.Mortgage{Space: 5},
+		engine.Unmortgage{Space: 12}, engine.Bid{Amount: 120}, engine.Pass{},
+		engine.ProposeTrade{Offer: engine.TradeOffer{
+			FromPlayer: 1, ToPlayer: 3, OfferedProps: []int{6, 8}, WantedProps: []int{9},
+			OfferedMoney: 40, OfferedJailCards: 1,
+		}},
+		engine.AcceptTrade{}, engine.DeclineTrade{}, engine.EndTurn{}, engine.PayDebt{},
+		engine.DeclareBankruptcy{}, engine.ReceiveMortgaged{}, engine.ReceiveMortgaged{Unmortgage: true},
+	}
+	seen := map[string]bool{}
+	for _, a := range actions {
+		w, err := EncodeAction(a)
+		if err != nil {
+			t.Fatalf("%T: %v", a, err)
+		}
+		seen[w.Type] = true
+		got, err := DecodeAction(w)
+		if err != nil {
+			t.Fatalf("%T: %v", a, err)
+		}
+		if !reflect.DeepEqual(got, a) {
+			t.Errorf("decoded %#v, want %#v", got, a)
+		}
+	}
+	for name := range actionTypes {
+		if !seen[name] {
+			t.Errorf("%s not covered", name)
+		}
+	}
+}
+
+func TestDecodeBadAction(t *testing.T) {
+	for _, w := range []*WireAction{
+		nil,
+		{Type: "Teleport", Data: []byte("{}")},
+		{Type: "Bid", Data: []byte(`{"Amount":"lots"}`)},
+	} {
+		if a, err := DecodeAction(w); err == nil {
+			t.Errorf("decoded %#v", a)
+		}
+	}
+	// Actions without fields may leave the data out
+	if a, err := DecodeAction(&WireAction{Type: "EndTurn"}); err != nil || a != (engine.EndTurn{}) {
+		t.Errorf("decoded %#v, %v", a, err)
+	}
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	events := []engine.Event{
+		engine.DiceRolled{Player: 1, Die1: 4, Die2: 4, Doubles: true},
+		engine.CardDrawn{Player: 2, Deck: engine.DeckCommunity, Card: board.CommunityChestCards()[3]},
+		engine.WentToJail{Player: 3, Reason: engine.JailThreeDoubles},
+		engine.TradeExecuted{Offer: engine.TradeOffer{
+			FromPlayer: 0, ToPlayer: 2, OfferedProps: []int{1, 3}, WantedMoney: 150, WantedJailCards: 1,
+		}},
+		engine.Bankrupt{Player: 1, Creditor: engine.Bank},
+	}
+	for _, typ := range eventTypes {
+		events = append(events, reflect.New(typ).Elem().Interface().(engine.Event))
+	}
+
+	wire, err := EncodeEvents(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeEvents(wire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("decoded %v, want %v", got, events)
+	}
+}
+
+func TestDecodeUnknownEvent(t *testing.T) {
+	if _, err := DecodeEvents([]*WireEvent{{Type: "Teleported", Data: []byte("{}")}}); err == nil {
+		t.Error("decoded an unknown event")
+	}
+}
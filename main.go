@@ -1,22 +1,52 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"time"
 
+	"github.com/AchrafSoltani/MoroccanMonopoly/ai"
 	"github.com/AchrafSoltani/MoroccanMonopoly/config"
 	"github.com/AchrafSoltani/MoroccanMonopoly/game"
 	"github.com/AchrafSoltani/glow"
 )
 
 func main() {
+	seed := flag.Int64("seed", 0, "random seed for dice and cards (0 = new seed each game)")
+	host := flag.String("host", "", "host LAN games on this address, e.g. :7777")
+	join := flag.String("join", "", "join the LAN game hosted at this address")
+	name := flag.String("name", "Guest", "your name when joining a LAN game")
+	apiAddr := flag.String("api", "", "serve the local control API on this loopback address, e.g. 127.0.0.1:7778")
+	flag.Parse()
+
+	if *seed == 0 {
+		// The AI thinks off the frame, so it can take its time; a fixed
+		// seed keeps to the fixed number of rollouts so its choices replay
+		ai.ThinkTime = 300 * time.Millisecond
+	}
+
 	win, err := glow.NewWindow(config.WindowTitle, config.WindowWidth, config.WindowHeight)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer win.Close()
 
-	g := game.NewGame()
+	g := game.NewGame(*seed)
+	switch {
+	case *host != "":
+		if err := g.HostLAN(*host); err != nil {
+			log.Fatal(err)
+		}
+	case *join != "":
+		if err := g.JoinLAN(*join, *name); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *apiAddr != "" {
+		if err := g.ServeAPI(*apiAddr); err != nil {
+			log.Fatal(err)
+		}
+	}
 	canvas := win.Canvas()
 	running := true
 	lastTime := time.Now()
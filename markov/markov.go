@@ -0,0 +1,292 @@
+// Package markov works out how often each space is landed on in the long
+// run, by treating a token's journey round the board as a Markov chain.
+// The chain follows the engine's rules: two dice, a roll again on doubles
+// with jail on the third, Go To Jail, and every card in the board's decks
+// that moves the token. It reads the layout and decks from the board it is
+// given, so a custom board or deck gets its own numbers.
+package markov
+
+import (
+	"math"
+	"sync"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+)
+
+// JailPlay is how a jailed player is assumed to get out.
+type JailPlay int
+
+const (
+	RollForDoubles JailPlay = iota // roll until doubles or the forced fine; usual late in a game
+	PayAtOnce                      // pay the fine and roll normally; usual early on
+)
+
+// Odds are the long-run landing frequencies for one board.
+type Odds struct {
+	// PerRoll is the chance a roll leaves the token on each space, after
+	// any card or Go To Jail has moved it. Rolls made from jail that fail
+	// to leave count as landing on the jail space.
+	PerRoll [config.SpaceCount]float64
+	// PerTurn is how many times a turn lands on each space on average;
+	// doubles make it more than PerRoll.
+	PerTurn [config.SpaceCount]float64
+	// InJail is the share of turns that start in jail.
+	InJail float64
+}
+
+// chain states: free on a space with 0-2 doubles already rolled this turn,
+// then in jail after 0 to MaxJailTurns-1 failed rolls.
+const (
+	freeStates = config.SpaceCount * 3
+	jailState  = freeStates
+	stateCount = freeStates + config.MaxJailTurns
+)
+
+func free(pos, doubles int) int { return doubles*config.SpaceCount + pos }
+
+// maxChain bounds how many card moves one landing follows, in case a
+// custom deck sends tokens round in a loop.
+const maxChain = 8
+
+// outcome is where a landing finally leaves the token.
+type outcome struct {
+	pos    int
+	jailed bool
+	p      float64
+}
+
+// step is one transition of the chain from a state before a roll.
+type step struct {
+	to   int // state before the next roll
+	land int // space the roll ended on
+	p    float64
+}
+
+// Compute builds the chain for b and solves it for its long-run
+// frequencies.
+func Compute(b *board.Board, jail JailPlay) *Odds {
+	landing := make([][]outcome, config.SpaceCount)
+	for pos := range landing {
+		landing[pos] = resolve(b, pos, 0)
+	}
+
+	steps := make([][]step, stateCount)
+	for d := 0; d < 3; d++ {
+		for pos := 0; pos < config.SpaceCount; pos++ {
+			steps[free(pos, d)] = freeSteps(landing, pos, d)
+		}
+	}
+	for j := 0; j < config.MaxJailTurns; j++ {
+		if jail == PayAtOnce {
+			steps[jailState+j] = freeSteps(landing, config.JailPosition, 0)
+		} else {
+			steps[jailState+j] = jailSteps(landing, j)
+		}
+	}
+
+	// Power iteration from a token on GO
+	pi := make([]float64, stateCount)
+	pi[free(config.GoPosition, 0)] = 1
+	next := make([]float64, stateCount)
+	for iter := 0; iter < 10000; iter++ {
+		clear(next)
+		for from, p := range pi {
+			for _, st := range steps[from] {
+				next[st.to] += p * st.p
+			}
+		}
+		diff := 0.0
+		for i := range pi {
+			diff += math.Abs(next[i] - pi[i])
+		}
+		pi, next = next, pi
+		if diff < 1e-13 {
+			break
+		}
+	}
+
+	o := &Odds{}
+	turnStarts := 0.0
+	for from, p := range pi {
+		if from < config.SpaceCount || from >= jailState {
+			turnStarts += p
+		}
+		if from >= jailState {
+			o.InJail += p
+		}
+		for _, st := range steps[from] {
+			o.PerRoll[st.land] += p * st.p
+		}
+	}
+	o.InJail /= turnStarts
+	for i := range o.PerRoll {
+		o.PerTurn[i] = o.PerRoll[i] / turnStarts
+	}
+	return o
+}
+
+// freeSteps are the transitions for a roll from pos with doubles already
+// rolled this turn.
+func freeSteps(landing [][]outcome, pos, doubles int) []step {
+	var steps []step
+	for d1 := 1; d1 <= 6; d1++ {
+		for d2 := 1; d2 <= 6; d2++ {
+			isDouble := d1 == d2
+			if isDouble && doubles == 2 {
+				steps = append(steps, step{to: jailState, land: config.JailPosition, p: 1.0 / 36})
+				continue
+			}
+			after := 0
+			if isDouble {
+				after = doubles + 1
+			}
+			steps = appendLanding(steps, landing[(pos+d1+d2)%config.SpaceCount], after, 1.0/36)
+		}
+	}
+	return steps
+}
+
+// jailSteps are the transitions for a roll from jail after failed rolls.
+func jailSteps(landing [][]outcome, failed int) []step {
+	var steps []step
+	for d1 := 1; d1 <= 6; d1++ {
+		for d2 := 1; d2 <= 6; d2++ {
+			to := landing[(config.JailPosition+d1+d2)%config.SpaceCount]
+			switch {
+			case d1 == d2:
+				// Out on doubles, which also earns another roll
+				steps = appendLanding(steps, to, 1, 1.0/36)
+			case failed+1 >= config.MaxJailTurns:
+				// Out on the forced fine
+				steps = appendLanding(steps, to, 0, 1.0/36)
+			default:
+				steps = append(steps, step{to: jailState + failed + 1, land: config.JailPosition, p: 1.0 / 36})
+			}
+		}
+	}
+	return steps
+}
+
+// appendLanding adds a roll of probability p that lands with outcomes to,
+// leaving doubles rolled this turn.
+func appendLanding(steps []step, to []outcome, doubles int, p float64) []step {
+	for _, o := range to {
+		st := step{to: free(o.pos, doubles), land: o.pos, p: p * o.p}
+		if o.jailed {
+			st.to = jailState
+		}
+		steps = append(steps, st)
+	}
+	return steps
+}
+
+// resolve follows a landing on pos through Go To Jail and the cards, as
+// resolveLanding and executeCard do in the engine.
+func resolve(b *board.Board, pos, depth int) []outcome {
+	var cards []board.Card
+	switch b.Spaces[pos].Type {
+	case board.SpaceGoToJail:
+		return []outcome{{pos: config.JailPosition, jailed: true, p: 1}}
+	case board.SpaceChance:
+		cards = b.ChanceDeck.Cards
+	case board.SpaceCommunityChest:
+		cards = b.CommunityDeck.Cards
+	}
+	if len(cards) == 0 || depth >= maxChain {
+		return []outcome{{pos: pos, p: 1}}
+	}
+
+	var out []outcome
+	p := 1.0 / float64(len(cards))
+	follow := func(target int) {
+		for _, o := range resolve(b, target, depth+1) {
+			out = append(out, outcome{pos: o.pos, jailed: o.jailed, p: p * o.p})
+		}
+	}
+	for _, c := range cards {
+		switch c.Effect {
+		case board.EffectMoveTo:
+			follow(c.Amount)
+		case board.EffectMoveSteps:
+			follow((pos + c.Amount + config.SpaceCount) % config.SpaceCount)
+		case board.EffectMoveNearest:
+			targets := b.RailroadSpaces()
+			if c.Amount != 1 {
+				targets = b.UtilitySpaces()
+			}
+			if len(targets) == 0 {
+				out = append(out, outcome{pos: pos, p: p})
+			} else {
+				follow(nearest(pos, targets))
+			}
+		case board.EffectGoToJail:
+			out = append(out, outcome{pos: config.JailPosition, jailed: true, p: p})
+		default:
+			out = append(out, outcome{pos: pos, p: p})
+		}
+	}
+	return out
+}
+
+// nearest is the first of targets clockwise from pos, as the engine finds
+// it.
+func nearest(from int, targets []int) int {
+	best, bestDist := targets[0], config.SpaceCount+1
+	for _, t := range targets {
+		dist := (t - from + config.SpaceCount) % config.SpaceCount
+		if dist == 0 {
+			dist = config.SpaceCount
+		}
+		if dist < bestDist {
+			best, bestDist = t, dist
+		}
+	}
+	return best
+}
+
+// cache holds the odds computed so far, by fingerprint.
+var cache sync.Map
+
+// For returns the odds for b with jailed players rolling for doubles,
+// computing them once per distinct layout and decks.
+func For(b *board.Board) *Odds {
+	key := fingerprint(b)
+	if o, ok := cache.Load(key); ok {
+		return o.(*Odds)
+	}
+	o, _ := cache.LoadOrStore(key, Compute(b, RollForDoubles))
+	return o.(*Odds)
+}
+
+// fingerprint hashes everything Compute reads from b. Deck order does not
+// matter, so shuffled decks of the same cards share a key.
+func fingerprint(b *board.Board) uint64 {
+	var key uint64 = 14695981039346656037
+	mix := func(v uint64) {
+		key ^= v
+		key *= 1099511628211
+	}
+	for _, sp := range b.Spaces {
+		mix(uint64(sp.Type))
+	}
+	for i, deck := range []*board.Deck{b.ChanceDeck, b.CommunityDeck} {
+		// A sum does not depend on the order of the cards
+		var sum uint64
+		for _, c := range deck.Cards {
+			sum += spread(uint64(i)<<48 ^ uint64(c.Effect)<<32 ^ uint64(uint32(c.Amount)))
+		}
+		mix(sum)
+	}
+	return key
+}
+
+// spread scatters the bits of v, so sums of different cards rarely
+// collide.
+func spread(v uint64) uint64 {
+	v ^= v >> 33
+	v *= 0xff51afd7ed558ccd
+	v ^= v >> 33
+	v *= 0xc4ceb9fe1a85ec53
+	return v ^ v>>33
+}
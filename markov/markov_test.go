@@ -0,0 +1,76 @@
+package markov
+
+import (
+	"math"
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+func TestCompute(t *testing.T) {
+	b := board.NewBoard(rng.New(1))
+	for _, tc := range []struct {
+		name string
+		jail JailPlay
+	}{
+		{"roll for doubles", RollForDoubles},
+		{"pay at once", PayAtOnce},
+	} {
+		o := Compute(b, tc.jail)
+		sum := 0.0
+		for i, p := range o.PerRoll {
+			sum += p
+			if o.PerTurn[i] < p {
+				t.Errorf("%s: space %d landed %v a turn, less than %v a roll", tc.name, i, o.PerTurn[i], p)
+			}
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("%s: landing odds add up to %v", tc.name, sum)
+		}
+		if o.PerRoll[config.GoToJailPos] != 0 {
+			t.Errorf("%s: a token stayed on Go To Jail", tc.name)
+		}
+		for i, p := range o.PerRoll {
+			if p > o.PerRoll[config.JailPosition] {
+				t.Errorf("%s: space %d landed on more than jail", tc.name, i)
+			}
+		}
+		if o.InJail <= 0 || o.InJail >= 1 {
+			t.Errorf("%s: %v of turns start in jail", tc.name, o.InJail)
+		}
+	}
+
+	if Compute(b, RollForDoubles).InJail <= Compute(b, PayAtOnce).InJail {
+		t.Error("rolling for doubles does not keep players in jail longer")
+	}
+}
+
+func TestCards(t *testing.T) {
+	b := board.NewBoard(rng.New(1))
+	with := Compute(b, RollForDoubles)
+	b.ChanceDeck.Cards = nil
+	without := Compute(b, RollForDoubles)
+
+	// Chance cards move tokens away from the Chance spaces
+	for i, sp := range b.Spaces {
+		if sp.Type == board.SpaceChance && without.PerRoll[i] <= with.PerRoll[i] {
+			t.Errorf("space %d: %v without cards, %v with", i, without.PerRoll[i], with.PerRoll[i])
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	a, b := board.NewBoard(rng.New(1)), board.NewBoard(rng.New(2))
+	if For(a) != For(b) {
+		t.Error("decks shuffled differently got different odds")
+	}
+	b.CommunityDeck.Cards = b.CommunityDeck.Cards[1:]
+	if For(a) == For(b) {
+		t.Error("a deck missing a card got the same odds")
+	}
+	if *For(a) != *Compute(a, RollForDoubles) {
+		t.Error("cached odds differ from computed ones")
+	}
+}
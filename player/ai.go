@@ -21,3 +21,26 @@ func (p *Player) ShouldBuy(price, totalProps int) bool {
 func (p *Player) ShouldBuild(houseCost int) bool {
 	return p.Money >= houseCost+AIBuildBuffer
 }
+
+// Difficulty selects how well an AI player plays.
+type Difficulty int
+
+const (
+	DifficultyNormal Difficulty = iota // the zero value, so older saves keep their AI
+	DifficultyEasy
+	DifficultyHard
+)
+
+// Difficulties lists the levels from weakest to strongest.
+var Difficulties = []Difficulty{DifficultyEasy, DifficultyNormal, DifficultyHard}
+
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyEasy:
+		return "Easy"
+	case DifficultyHard:
+		return "Hard"
+	default:
+		return "Normal"
+	}
+}
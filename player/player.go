@@ -7,6 +7,8 @@ type Player struct {
 	ID       int
 	Name     string
 	IsAI     bool
+	Difficulty Difficulty // how an AI player plays; unused for humans
+	Color    int        // token colour, 0 to config.TokenColors-1
 	Money    int
 	Position int
 	InJail   bool
@@ -22,6 +24,7 @@ func NewPlayer(id int, name string, isAI bool) *Player {
 		ID:    id,
 		Name:  name,
 		IsAI:  isAI,
+		Color: id % config.TokenColors,
 		Money: config.StartingMoney,
 	}
 }
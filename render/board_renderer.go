@@ -6,6 +6,7 @@ import (
 
 	"github.com/AchrafSoltani/MoroccanMonopoly/board"
 	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
 	"github.com/AchrafSoltani/glow"
 )
 
@@ -326,15 +327,16 @@ func (br *BoardRenderer) drawHotelIndicator(canvas *glow.Canvas, r SpaceRect, si
 	}
 }
 
-// DrawOwnershipDots draws small dots on properties to show ownership.
-func (br *BoardRenderer) DrawOwnershipDots(canvas *glow.Canvas, b *board.Board) {
+// DrawOwnershipDots draws small dots on properties to show ownership, in
+// each owner's token colour.
+func (br *BoardRenderer) DrawOwnershipDots(canvas *glow.Canvas, b *board.Board, players []*player.Player) {
 	for i := 0; i < config.SpaceCount; i++ {
 		prop := b.Properties[i]
 		if prop.OwnerID < 0 {
 			continue
 		}
 		r := br.SpaceRects[i]
-		col := PlayerColors[prop.OwnerID%4]
+		col := PlayerColors[players[prop.OwnerID].Color]
 		if prop.Mortgaged {
 			col = MortgageColor
 		}
@@ -345,6 +347,53 @@ func (br *BoardRenderer) DrawOwnershipDots(canvas *glow.Canvas, b *board.Board)
 	}
 }
 
+// DrawHeatmap tints each space by its value against the largest, from
+// pale yellow to deep red, writes its label over the middle, and names the
+// view in the centre of the board. Spaces valued zero are left alone.
+func (br *BoardRenderer) DrawHeatmap(canvas *glow.Canvas, values [config.SpaceCount]float64, labels [config.SpaceCount]string, title string) {
+	top := 0.0
+	for _, v := range values {
+		top = max(top, v)
+	}
+
+	for i, v := range values {
+		if v <= 0 || top <= 0 {
+			continue
+		}
+		r := br.SpaceRects[i]
+		col := heatColor(v / top)
+		for dy := 0; dy < r.H; dy++ {
+			for dx := dy % 2; dx < r.W; dx += 2 {
+				canvas.SetPixel(r.X+dx, r.Y+dy, col)
+			}
+		}
+
+		// Label on a plain strip so it stays readable over the tint
+		if labels[i] != "" {
+			w := len(labels[i])*8 + 4
+			cx, cy := r.X+r.W/2, r.Y+r.H/2
+			canvas.DrawRect(cx-w/2, cy-6, w, 12, SpaceBg)
+			DrawTextCentered(canvas, labels[i], cx, cy-4, TextDark, 1)
+		}
+	}
+
+	l := br.Layout
+	centerX := l.BoardX + l.BoardSize/2
+	centerY := l.BoardY + l.BoardSize/2
+	DrawTextCentered(canvas, title, centerX, centerY+75, TextDark, 1)
+	DrawTextCentered(canvas, "O - next view", centerX, centerY+90, TextDark, 1)
+}
+
+// heatColor shades from pale yellow at 0 to deep red at 1.
+func heatColor(t float64) glow.Color {
+	t = min(max(t, 0), 1)
+	return glow.Color{
+		R: uint8(255 - 55*t),
+		G: uint8(230 - 210*t),
+		B: uint8(140 - 120*t),
+	}
+}
+
 // Helper functions
 
 // spaceSide returns which side of the board a space is on:
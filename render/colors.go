@@ -1,6 +1,9 @@
 package render
 
-import "github.com/AchrafSoltani/glow"
+import (
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/glow"
+)
 
 // Moroccan colour palette
 var (
@@ -42,12 +45,19 @@ var (
 	DialogBg    = glow.Color{R: 40, G: 40, B: 40}
 	DialogBorder = glow.Color{R: 180, G: 140, B: 60}
 
-	// Player token colours
-	PlayerColors = [4]glow.Color{
+	// Player token colours, indexed by Player.Color
+	PlayerColors = [config.TokenColors]glow.Color{
 		{R: 220, G: 50, B: 50},   // red
 		{R: 50, G: 120, B: 220},  // blue
 		{R: 50, G: 180, B: 50},   // green
 		{R: 220, G: 180, B: 50},  // gold
+		{R: 170, G: 80, B: 200},  // purple
+		{R: 240, G: 130, B: 40},  // orange
+		{R: 40, G: 190, B: 190},  // teal
+		{R: 230, G: 110, B: 170}, // pink
+	}
+	PlayerColorNames = [config.TokenColors]string{
+		"Red", "Blue", "Green", "Gold", "Purple", "Orange", "Teal", "Pink",
 	}
 
 	// Moroccan decorative colours
@@ -8,12 +8,23 @@ import (
 
 // PlayerInfo holds the data the HUD needs about a player.
 type PlayerInfo struct {
-	ID       int
-	Name     string
-	Money    int
-	Bankrupt bool
-	InJail   bool
-	IsAI     bool
+	ID         int
+	Name       string
+	Money      int
+	Bankrupt   bool
+	InJail     bool
+	IsAI       bool
+	Difficulty string // AI level, e.g. "Hard"
+	Color      int    // index into PlayerColors
+	CashHidden bool   // hot-seat privacy: show "???" instead of Money
+}
+
+// cash formats a player's money for the HUD.
+func (p PlayerInfo) cash() string {
+	if p.CashHidden {
+		return "??? MAD"
+	}
+	return fmt.Sprintf("%d MAD", p.Money)
 }
 
 // HUDData holds all the data the HUD needs to render.
@@ -23,6 +34,8 @@ type HUDData struct {
 	Messages        []string
 	Die1, Die2      int
 	Phase           string
+	Seed            int64
+	Jackpot         int // Free Parking pot; negative when the house rule is off
 }
 
 // DrawHUD renders the right-side info panel.
@@ -46,15 +59,15 @@ func DrawHUD(canvas *glow.Canvas, data HUDData, panelX, panelWidth, winHeight in
 
 	for _, p := range data.Players {
 		if p.ID == data.CurrentPlayerID {
-			col := PlayerColors[p.ID%4]
-			DrawTokenAt(canvas, p.ID, px+25, y+6, 5)
+			col := PlayerColors[p.Color]
+			DrawTokenAt(canvas, p.ID, p.Color, px+25, y+6, 5)
 			tag := ""
 			if p.IsAI {
-				tag = " (AI)"
+				tag = " (AI, " + p.Difficulty + ")"
 			}
 			DrawText(canvas, fmt.Sprintf("%s%s", p.Name, tag), px+40, y, col, 1)
 			y += 12
-			DrawText(canvas, fmt.Sprintf("Money: %d MAD", p.Money), px+40, y, TextLight, 1)
+			DrawText(canvas, "Money: "+p.cash(), px+40, y, TextLight, 1)
 			if p.InJail {
 				y += 12
 				DrawText(canvas, "** IN JAIL **", px+40, y, ColorRed, 1)
@@ -81,6 +94,13 @@ func DrawHUD(canvas *glow.Canvas, data HUDData, panelX, panelWidth, winHeight in
 	if data.Phase != "" {
 		DrawText(canvas, data.Phase, px+15, y, glow.Color{R: 150, G: 180, B: 150}, 1)
 	}
+	y += 12
+
+	// Seed, so a game can be reported and replayed
+	DrawText(canvas, fmt.Sprintf("Seed: %d", data.Seed), px+15, y, glow.Color{R: 110, G: 130, B: 110}, 1)
+	if data.Jackpot >= 0 {
+		DrawTextRight(canvas, fmt.Sprintf("Free Parking: %d MAD", data.Jackpot), px+pw-15, y, TextGold, 1)
+	}
 	y += 16
 
 	canvas.DrawLine(px+10, y, px+pw-10, y, PanelBorder)
@@ -91,8 +111,8 @@ func DrawHUD(canvas *glow.Canvas, data HUDData, panelX, panelWidth, winHeight in
 	y += 14
 
 	for _, p := range data.Players {
-		col := PlayerColors[p.ID%4]
-		status := fmt.Sprintf("%d MAD", p.Money)
+		col := PlayerColors[p.Color]
+		status := p.cash()
 		if p.Bankrupt {
 			status = "BANKRUPT"
 			col = MortgageColor
@@ -109,7 +129,7 @@ func DrawHUD(canvas *glow.Canvas, data HUDData, panelX, panelWidth, winHeight in
 			indicator = "> "
 		}
 
-		DrawTokenAt(canvas, p.ID, px+25, y+4, 4)
+		DrawTokenAt(canvas, p.ID, p.Color, px+25, y+4, 4)
 		text := fmt.Sprintf("%s%s%s: %s", indicator, tag, p.Name, status)
 		DrawText(canvas, text, px+38, y, col, 1)
 		y += 14
@@ -19,7 +19,7 @@ const (
 
 // DrawToken draws a player's token on the board at the given space rect.
 func DrawToken(canvas *glow.Canvas, p *player.Player, r SpaceRect) {
-	col := PlayerColors[p.ID%4]
+	col := PlayerColors[p.Color]
 	shape := TokenShape(p.ID % 4)
 
 	var cx, cy int
@@ -63,7 +63,7 @@ func DrawToken(canvas *glow.Canvas, p *player.Player, r SpaceRect) {
 
 // DrawTokenHighlight draws a pulsing ring around the current player's token.
 func DrawTokenHighlight(canvas *glow.Canvas, p *player.Player, r SpaceRect, timer float64) {
-	col := PlayerColors[p.ID%4]
+	col := PlayerColors[p.Color]
 
 	var cx, cy int
 	if p.InJail {
@@ -86,8 +86,9 @@ func DrawTokenHighlight(canvas *glow.Canvas, p *player.Player, r SpaceRect, time
 }
 
 // DrawTokenAt draws a token at an arbitrary position (for HUD/dialogs).
-func DrawTokenAt(canvas *glow.Canvas, playerID int, cx, cy, size int) {
-	col := PlayerColors[playerID%4]
+// The shape follows the seat and the colour is the player's choice.
+func DrawTokenAt(canvas *glow.Canvas, playerID, color int, cx, cy, size int) {
+	col := PlayerColors[color]
 	shape := TokenShape(playerID % 4)
 
 	switch shape {
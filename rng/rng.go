@@ -0,0 +1,75 @@
+// Package rng provides the seeded random source a game draws all of its
+// randomness from: dice, deck shuffles and AI choices. Two games started
+// from the same seed with the same decisions play out identically.
+package rng
+
+import "time"
+
+// Rand is a small deterministic generator (SplitMix64). Its whole state is
+// one integer, so it can be saved and restored exactly.
+type Rand struct {
+	seed  int64
+	state uint64
+}
+
+// New returns a generator started from seed.
+func New(seed int64) *Rand {
+	return &Rand{seed: seed, state: uint64(seed)}
+}
+
+// NewSeed returns a fresh seed from the clock, for games started without one.
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// Seed returns the seed the generator was started from.
+func (r *Rand) Seed() int64 {
+	return r.seed
+}
+
+// State returns the current position in the sequence.
+func (r *Rand) State() uint64 {
+	return r.state
+}
+
+// SetState resumes the sequence from a value returned by State.
+func (r *Rand) SetState(state uint64) {
+	r.state = state
+}
+
+// Uint64 returns the next 64 random bits.
+func (r *Rand) Uint64() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a uniform integer in [0, n). It panics if n <= 0.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("rng: invalid argument to Intn")
+	}
+	// Reject the top sliver of the range so every result is equally likely
+	bound := uint64(n)
+	limit := -bound % bound
+	for {
+		v := r.Uint64()
+		if v >= limit {
+			return int(v % bound)
+		}
+	}
+}
+
+// Float64 returns a uniform float in [0, 1).
+func (r *Rand) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// Shuffle randomises the order of n elements using swap (Fisher-Yates).
+func (r *Rand) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, r.Intn(i+1))
+	}
+}
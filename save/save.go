@@ -1,19 +1,16 @@
 package save
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
-
 	"github.com/AchrafSoltani/MoroccanMonopoly/board"
 	"github.com/AchrafSoltani/MoroccanMonopoly/config"
 )
 
 const saveDir = ".config/moroccan-monopoly"
-const saveFile = "save.json"
 
 // SaveData represents the full game state for serialisation.
 type SaveData struct {
+	Version    int                                  `json:"version"`
+	Meta       SlotMeta                             `json:"meta"`
 	Players    []PlayerData                         `json:"players"`
 	Current    int                                  `json:"current"`
 	Properties [config.SpaceCount]PropertyData      `json:"properties"`
@@ -21,7 +18,26 @@ type SaveData struct {
 	HotelPool  int                                  `json:"hotel_pool"`
 	Die1       int                                  `json:"die1"`
 	Die2       int                                  `json:"die2"`
+	Rules      RulesData                            `json:"rules"`
+	Jackpot    int                                  `json:"jackpot"`
 	Messages   []string                             `json:"messages"`
+	Stats      StatsData                            `json:"stats"`
+
+	Turn          TurnData          `json:"turn"`
+	Auction       *AuctionData      `json:"auction,omitempty"`
+	PendingTrade  *PendingTradeData `json:"pending_trade,omitempty"`
+	Debts         *DebtsData        `json:"debts,omitempty"`
+	Transfers     *TransfersData    `json:"transfers,omitempty"`
+	AuctionQueue  []int             `json:"auction_queue,omitempty"` // bank lots awaiting auction
+	ChanceDeck    DeckData          `json:"chance_deck"`
+	CommunityDeck DeckData          `json:"community_deck"`
+	RNG           RNGData           `json:"rng"`
+
+	// Presentation state
+	Dialog       int               `json:"dialog"`
+	TradeBuilder *TradeBuilderData `json:"trade_builder,omitempty"`
+	HandOff      bool              `json:"hand_off,omitempty"`    // hot-seat hand-off screen between humans
+	HiddenCash   bool              `json:"hidden_cash,omitempty"` // HUD shows only the viewer's cash
 }
 
 // PlayerData is the serialisable player state.
@@ -29,6 +45,8 @@ type PlayerData struct {
 	ID                int    `json:"id"`
 	Name              string `json:"name"`
 	IsAI              bool   `json:"is_ai"`
+	Difficulty        int    `json:"difficulty,omitempty"`
+	Color             int    `json:"color"`
 	Money             int    `json:"money"`
 	Position          int    `json:"position"`
 	InJail            bool   `json:"in_jail"`
@@ -45,57 +63,6 @@ type PropertyData struct {
 	Mortgaged bool `json:"mortgaged"`
 }
 
-func savePath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		home = "."
-	}
-	return filepath.Join(home, saveDir, saveFile)
-}
-
-// Save writes game state to disk.
-func Save(data *SaveData) error {
-	path := savePath()
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(path, jsonData, 0644)
-}
-
-// Load reads game state from disk.
-func Load() (*SaveData, error) {
-	path := savePath()
-	jsonData, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	data := &SaveData{}
-	if err := json.Unmarshal(jsonData, data); err != nil {
-		return nil, err
-	}
-
-	return data, nil
-}
-
-// HasSave checks if a save file exists.
-func HasSave() bool {
-	_, err := os.Stat(savePath())
-	return err == nil
-}
-
-// DeleteSave removes the save file.
-func DeleteSave() {
-	os.Remove(savePath())
-}
-
 // BoardToPropertyData converts board properties to saveable format.
 func BoardToPropertyData(b *board.Board) [config.SpaceCount]PropertyData {
 	var data [config.SpaceCount]PropertyData
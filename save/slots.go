@@ -0,0 +1,222 @@
+package save
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AutosaveHistory is how many autosaves are kept; older ones are deleted.
+const AutosaveHistory = 5
+
+const (
+	slotExt        = ".json"
+	autosavePrefix = "autosave-"
+)
+
+// SlotMeta describes a saved game for the slot list, without loading it.
+type SlotMeta struct {
+	Name     string       `json:"name"`
+	SavedAt  time.Time    `json:"saved_at"`
+	Round    int          `json:"round"`
+	Autosave bool         `json:"autosave"`
+	Players  []SlotPlayer `json:"players"`
+}
+
+// SlotPlayer is one seat in the slot metadata.
+type SlotPlayer struct {
+	Name     string `json:"name"`
+	IsAI     bool   `json:"is_ai"`
+	NetWorth int    `json:"net_worth"`
+	Bankrupt bool   `json:"bankrupt"`
+}
+
+// Slot is a saved game on disk. Err is set when the file could not be read,
+// so a broken slot can still be listed and deleted.
+type Slot struct {
+	ID   string
+	Meta SlotMeta
+	Err  error
+}
+
+func slotDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, saveDir)
+}
+
+func slotPath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid slot id %q", id)
+	}
+	return filepath.Join(slotDir(), id+slotExt), nil
+}
+
+// NewSlotID returns an ID for a new manual save slot.
+func NewSlotID() string {
+	return "game-" + time.Now().Format("20060102-150405")
+}
+
+// ListSlots returns every saved game, newest first.
+func ListSlots() ([]Slot, error) {
+	entries, err := os.ReadDir(slotDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []Slot
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), slotExt) {
+			continue
+		}
+		slot := Slot{ID: strings.TrimSuffix(e.Name(), slotExt)}
+		data, err := readSlot(slot.ID)
+		if err != nil {
+			slot.Err = err
+		} else {
+			slot.Meta = data.Meta
+		}
+		// Broken and migrated saves have no save time of their own
+		if info, err := e.Info(); err == nil && slot.Meta.SavedAt.IsZero() {
+			slot.Meta.SavedAt = info.ModTime()
+		}
+		if slot.Meta.Name == "" {
+			slot.Meta.Name = slot.ID
+		}
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Meta.SavedAt.After(slots[j].Meta.SavedAt)
+	})
+	return slots, nil
+}
+
+// HasSlots reports whether any saved game exists.
+func HasSlots() bool {
+	slots, _ := ListSlots()
+	return len(slots) > 0
+}
+
+// LoadSlot reads a saved game, migrating older formats, and rejects saves
+// that fail Validate.
+func LoadSlot(id string) (*SaveData, error) {
+	data, err := readSlot(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// SaveSlot writes a game to the slot, replacing what was there. An empty
+// name defaults to the players' names.
+func SaveSlot(id string, data *SaveData) error {
+	data.Version = Version
+	data.Meta.SavedAt = time.Now()
+	if data.Meta.Name == "" {
+		data.Meta.Name = defaultName(data)
+	}
+	return writeSlot(id, data)
+}
+
+// DeleteSlot removes a saved game.
+func DeleteSlot(id string) error {
+	path, err := slotPath(id)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// RenameSlot changes the display name of a saved game.
+func RenameSlot(id, name string) error {
+	data, err := readSlot(id)
+	if err != nil {
+		return err
+	}
+	data.Meta.Name = name
+	return writeSlot(id, data)
+}
+
+// Autosave writes a new autosave slot and deletes all but the newest
+// AutosaveHistory autosaves.
+func Autosave(data *SaveData) error {
+	data.Meta.Autosave = true
+	data.Meta.Name = fmt.Sprintf("Autosave - %s, round %d", defaultName(data), data.Meta.Round)
+	id := fmt.Sprintf("%s%d", autosavePrefix, time.Now().UnixNano())
+	if err := SaveSlot(id, data); err != nil {
+		return err
+	}
+
+	slots, err := ListSlots()
+	if err != nil {
+		return err
+	}
+	kept := 0
+	for _, s := range slots {
+		if !strings.HasPrefix(s.ID, autosavePrefix) {
+			continue
+		}
+		kept++
+		if kept > AutosaveHistory {
+			DeleteSlot(s.ID)
+		}
+	}
+	return nil
+}
+
+// readSlot reads and migrates a slot without validating it.
+func readSlot(id string) (*SaveData, error) {
+	path, err := slotPath(id)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decode(jsonData)
+}
+
+// writeSlot writes through a temporary file so a crash mid-write never
+// leaves a half-written save in place of a good one.
+func writeSlot(id string, data *SaveData) error {
+	path, err := slotPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, jsonData, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// defaultName names a slot after its players, e.g. "Player 1 vs AI Player".
+func defaultName(data *SaveData) string {
+	var names []string
+	for _, p := range data.Players {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, " vs ")
+}
@@ -0,0 +1,433 @@
+package save
+
+import (
+	"slices"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// RulesData is the serialisable engine.RuleSet.
+type RulesData struct {
+	StartingMoney      int  `json:"starting_money"`
+	GoSalary           int  `json:"go_salary"`
+	JailFine           int  `json:"jail_fine"`
+	MaxJailTurns       int  `json:"max_jail_turns"`
+	MortgageRate       int  `json:"mortgage_rate"`
+	InterestRate       int  `json:"interest_rate"`
+	FreeParkingJackpot bool `json:"free_parking_jackpot"`
+	DoubleSalary       bool `json:"double_salary"`
+	NoAuctions         bool `json:"no_auctions"`
+	NoRentInJail       bool `json:"no_rent_in_jail"`
+	EvenBuild          bool `json:"even_build"`
+}
+
+// TurnData is the serialisable position within the current turn.
+type TurnData struct {
+	Phase        int  `json:"phase"`
+	Round        int  `json:"round"`
+	Doubles      bool `json:"doubles"`
+	DoublesCount int  `json:"doubles_count"`
+	Trades       int  `json:"trades_proposed"`
+}
+
+// AuctionData is the serialisable state of an auction in progress.
+type AuctionData struct {
+	SpaceIdx    int                     `json:"space_idx"`
+	Active      [config.MaxPlayers]bool `json:"active"`
+	Current     int                     `json:"current"`
+	HighBid     int                     `json:"high_bid"`
+	HighBidder  int                     `json:"high_bidder"`
+	ReturnPhase int                     `json:"return_phase"`
+}
+
+// TradeOfferData is the serialisable form of an engine.TradeOffer.
+type TradeOfferData struct {
+	FromPlayer       int   `json:"from_player"`
+	ToPlayer         int   `json:"to_player"`
+	OfferedProps     []int `json:"offered_props"`
+	WantedProps      []int `json:"wanted_props"`
+	OfferedMoney     int   `json:"offered_money"`
+	WantedMoney      int   `json:"wanted_money"`
+	OfferedJailCards int   `json:"offered_jail_cards"`
+	WantedJailCards  int   `json:"wanted_jail_cards"`
+}
+
+// PendingTradeData is an offer awaiting its recipient's answer.
+type PendingTradeData struct {
+	Offer       TradeOfferData `json:"offer"`
+	ReturnPhase int            `json:"return_phase"`
+}
+
+// DebtData is a debt the debtor has not yet raised the cash for.
+type DebtData struct {
+	Debtor   int  `json:"debtor"`
+	Creditor int  `json:"creditor"` // -1 for the bank
+	Amount   int  `json:"amount"`
+	Rent     bool `json:"rent,omitempty"` // rent for landing on Space
+	Space    int  `json:"space,omitempty"`
+}
+
+// DebtsData is the debt phase in progress: the debts in the order they are
+// settled and the phase to resume afterwards.
+type DebtsData struct {
+	Owed        []DebtData `json:"owed"`
+	ReturnPhase int        `json:"return_phase"`
+}
+
+// TransferData is a mortgaged property awaiting its new owner's decision.
+type TransferData struct {
+	Player int `json:"player"`
+	Space  int `json:"space"`
+}
+
+// TransfersData is the mortgaged transfers in progress and the phase to
+// resume afterwards.
+type TransfersData struct {
+	Pending     []TransferData `json:"pending"`
+	ReturnPhase int            `json:"return_phase"`
+}
+
+// StatsData is the serialisable engine.Stats.
+type StatsData struct {
+	Turns        int                    `json:"turns"`
+	Rolls        int                    `json:"rolls"`
+	Doubles      int                    `json:"doubles"`
+	Trades       int                    `json:"trades"`
+	Landings     [config.SpaceCount]int `json:"landings"`
+	RentPaid     [config.MaxPlayers]int `json:"rent_paid"`
+	RentReceived [config.MaxPlayers]int `json:"rent_received"`
+	Purchases    [config.MaxPlayers]int `json:"purchases"`
+	HousesBuilt  [config.MaxPlayers]int `json:"houses_built"`
+	JailVisits   [config.MaxPlayers]int `json:"jail_visits"`
+	Eliminated   []int                  `json:"eliminated"`
+}
+
+// DeckData records a deck's order as indices into its standard card list,
+// plus the position of the next card to draw.
+type DeckData struct {
+	Order []int `json:"order"`
+	Next  int   `json:"next"`
+}
+
+// RNGData records the random source so dice and shuffles continue exactly.
+type RNGData struct {
+	Seed  int64  `json:"seed"`
+	State uint64 `json:"state"`
+}
+
+// TradeBuilderData is an offer still being put together in the trade dialog.
+type TradeBuilderData struct {
+	Stage int            `json:"stage"`
+	Offer TradeOfferData `json:"offer"`
+}
+
+// FromState captures everything needed to resume s exactly, along with the
+// slot metadata derived from it. Presentation state (messages, open dialog)
+// and the slot name are left for the caller to fill in. The result shares
+// nothing with s, so it stays valid as play goes on.
+func FromState(s *engine.GameState) *SaveData {
+	data := &SaveData{
+		Version:    Version,
+		Meta:       SlotMeta{Round: s.Round},
+		Current:    s.Current,
+		Properties: BoardToPropertyData(s.Board),
+		HousePool:  s.Board.HousePool,
+		HotelPool:  s.Board.HotelPool,
+		Die1:       s.Die1,
+		Die2:       s.Die2,
+		Rules:      RulesToData(s.Rules),
+		Jackpot:    s.Jackpot,
+		Turn: TurnData{
+			Phase:        int(s.Phase),
+			Round:        s.Round,
+			Doubles:      s.Doubles,
+			DoublesCount: s.DoublesCount,
+			Trades:       s.TradesProposed,
+		},
+		ChanceDeck:    deckToData(s.Board.ChanceDeck, board.ChanceCards()),
+		CommunityDeck: deckToData(s.Board.CommunityDeck, board.CommunityChestCards()),
+		RNG:           RNGData{Seed: s.Rand.Seed(), State: s.Rand.State()},
+		AuctionQueue:  slices.Clone(s.AuctionQueue),
+	}
+
+	for _, p := range s.Players {
+		data.Players = append(data.Players, PlayerData{
+			ID:                p.ID,
+			Name:              p.Name,
+			IsAI:              p.IsAI,
+			Difficulty:        int(p.Difficulty),
+			Color:             p.Color,
+			Money:             p.Money,
+			Position:          p.Position,
+			InJail:            p.InJail,
+			JailTurns:         p.JailTurns,
+			Bankrupt:          p.Bankrupt,
+			Properties:        slices.Clone(p.Properties),
+			GetOutOfJailCards: p.GetOutOfJailCards,
+		})
+		data.Meta.Players = append(data.Meta.Players, SlotPlayer{
+			Name:     p.Name,
+			IsAI:     p.IsAI,
+			NetWorth: s.PlayerNetWorth(p.ID),
+			Bankrupt: p.Bankrupt,
+		})
+	}
+
+	if s.Phase == engine.PhaseAuction {
+		data.Auction = &AuctionData{
+			SpaceIdx:    s.AuctionSpaceIdx,
+			Active:      s.AuctionActive,
+			Current:     s.AuctionCurrent,
+			HighBid:     s.AuctionHighBid,
+			HighBidder:  s.AuctionHighBidder,
+			ReturnPhase: int(s.AuctionReturnPhase),
+		}
+	}
+	if s.PendingOffer != nil {
+		data.PendingTrade = &PendingTradeData{
+			Offer:       OfferToData(*s.PendingOffer),
+			ReturnPhase: int(s.TradeReturnPhase),
+		}
+	}
+	if len(s.Debts) > 0 {
+		data.Debts = &DebtsData{ReturnPhase: int(s.DebtReturnPhase)}
+		for _, debt := range s.Debts {
+			data.Debts.Owed = append(data.Debts.Owed, DebtData{
+				Debtor:   debt.Debtor,
+				Creditor: debt.Creditor,
+				Amount:   debt.Amount,
+				Rent:     debt.Rent,
+				Space:    debt.Space,
+			})
+		}
+	}
+	if len(s.Transfers) > 0 {
+		data.Transfers = &TransfersData{ReturnPhase: int(s.TransferReturnPhase)}
+		for _, t := range s.Transfers {
+			data.Transfers.Pending = append(data.Transfers.Pending, TransferData{Player: t.Player, Space: t.Space})
+		}
+	}
+	return data
+}
+
+// Public returns a copy of d for a LAN client. It leaves out the random
+// source and the order of the decks, which would tell the dice and cards to
+// come. Cash stays in, as the client's engine checks moves against it;
+// HiddenCash tells the client to keep other players' cash off the screen.
+func (d *SaveData) Public() *SaveData {
+	pub := *d
+	pub.RNG = RNGData{}
+	pub.ChanceDeck = DeckData{}
+	pub.CommunityDeck = DeckData{}
+	return &pub
+}
+
+// Restore rebuilds the game state recorded in d, resuming at the same point
+// of the same turn with the random source where it left off.
+func (d *SaveData) Restore() *engine.GameState {
+	var players []*player.Player
+	for _, pd := range d.Players {
+		p := player.NewPlayer(pd.ID, pd.Name, pd.IsAI)
+		p.Difficulty = player.Difficulty(pd.Difficulty)
+		p.Color = pd.Color
+		p.Position = pd.Position
+		p.InJail = pd.InJail
+		p.JailTurns = pd.JailTurns
+		p.Bankrupt = pd.Bankrupt
+		p.Properties = slices.Clone(pd.Properties)
+		p.GetOutOfJailCards = pd.GetOutOfJailCards
+		players = append(players, p)
+	}
+
+	r := rng.New(d.RNG.Seed)
+	s := engine.New(players, r, DataToRules(d.Rules))
+	for i, pd := range d.Players {
+		players[i].Money = pd.Money // New dealt out the starting money
+	}
+	s.Jackpot = d.Jackpot
+	PropertyDataToBoard(s.Board, d.Properties)
+	s.Board.HousePool = d.HousePool
+	s.Board.HotelPool = d.HotelPool
+	dataToDeck(s.Board.ChanceDeck, d.ChanceDeck, board.ChanceCards())
+	dataToDeck(s.Board.CommunityDeck, d.CommunityDeck, board.CommunityChestCards())
+
+	s.Current = d.Current
+	s.Phase = engine.TurnPhase(d.Turn.Phase)
+	s.Round = d.Turn.Round
+	s.Die1 = d.Die1
+	s.Die2 = d.Die2
+	s.Doubles = d.Turn.Doubles
+	s.DoublesCount = d.Turn.DoublesCount
+	s.TradesProposed = d.Turn.Trades
+	s.AuctionQueue = slices.Clone(d.AuctionQueue)
+
+	if a := d.Auction; a != nil {
+		s.AuctionSpaceIdx = a.SpaceIdx
+		s.AuctionActive = a.Active
+		s.AuctionCurrent = a.Current
+		s.AuctionHighBid = a.HighBid
+		s.AuctionHighBidder = a.HighBidder
+		s.AuctionReturnPhase = engine.TurnPhase(a.ReturnPhase)
+	}
+	if t := d.PendingTrade; t != nil {
+		offer := DataToOffer(t.Offer)
+		s.PendingOffer = &offer
+		s.TradeReturnPhase = engine.TurnPhase(t.ReturnPhase)
+	}
+	if debts := d.Debts; debts != nil {
+		for _, debt := range debts.Owed {
+			s.Debts = append(s.Debts, engine.Debt{
+				Debtor:   debt.Debtor,
+				Creditor: debt.Creditor,
+				Amount:   debt.Amount,
+				Rent:     debt.Rent,
+				Space:    debt.Space,
+			})
+		}
+		s.DebtReturnPhase = engine.TurnPhase(debts.ReturnPhase)
+	}
+	if t := d.Transfers; t != nil {
+		for _, tr := range t.Pending {
+			s.Transfers = append(s.Transfers, engine.Transfer{Player: tr.Player, Space: tr.Space})
+		}
+		s.TransferReturnPhase = engine.TurnPhase(t.ReturnPhase)
+	}
+
+	// Building the board shuffled the decks; rewind to the saved position
+	r.SetState(d.RNG.State)
+	return s
+}
+
+// RulesToData converts a rule set to its serialisable form.
+func RulesToData(r engine.RuleSet) RulesData {
+	return RulesData{
+		StartingMoney:      r.StartingMoney,
+		GoSalary:           r.GoSalary,
+		JailFine:           r.JailFine,
+		MaxJailTurns:       r.MaxJailTurns,
+		MortgageRate:       r.MortgageRate,
+		InterestRate:       r.InterestRate,
+		FreeParkingJackpot: r.FreeParkingJackpot,
+		DoubleSalary:       r.DoubleSalary,
+		NoAuctions:         r.NoAuctions,
+		NoRentInJail:       r.NoRentInJail,
+		EvenBuild:          r.EvenBuild,
+	}
+}
+
+// DataToRules converts a saved rule set back to an engine.RuleSet.
+func DataToRules(d RulesData) engine.RuleSet {
+	return engine.RuleSet{
+		StartingMoney:      d.StartingMoney,
+		GoSalary:           d.GoSalary,
+		JailFine:           d.JailFine,
+		MaxJailTurns:       d.MaxJailTurns,
+		MortgageRate:       d.MortgageRate,
+		InterestRate:       d.InterestRate,
+		FreeParkingJackpot: d.FreeParkingJackpot,
+		DoubleSalary:       d.DoubleSalary,
+		NoAuctions:         d.NoAuctions,
+		NoRentInJail:       d.NoRentInJail,
+		EvenBuild:          d.EvenBuild,
+	}
+}
+
+// StatsToData converts game statistics to their serialisable form.
+func StatsToData(st *engine.Stats) StatsData {
+	return StatsData{
+		Turns:        st.Turns,
+		Rolls:        st.Rolls,
+		Doubles:      st.Doubles,
+		Trades:       st.Trades,
+		Landings:     st.Landings,
+		RentPaid:     st.RentPaid,
+		RentReceived: st.RentReceived,
+		Purchases:    st.Purchases,
+		HousesBuilt:  st.HousesBuilt,
+		JailVisits:   st.JailVisits,
+		Eliminated:   slices.Clone(st.Eliminated),
+	}
+}
+
+// DataToStats converts saved statistics back to engine.Stats.
+func DataToStats(d StatsData) engine.Stats {
+	return engine.Stats{
+		Turns:        d.Turns,
+		Rolls:        d.Rolls,
+		Doubles:      d.Doubles,
+		Trades:       d.Trades,
+		Landings:     d.Landings,
+		RentPaid:     d.RentPaid,
+		RentReceived: d.RentReceived,
+		Purchases:    d.Purchases,
+		HousesBuilt:  d.HousesBuilt,
+		JailVisits:   d.JailVisits,
+		Eliminated:   slices.Clone(d.Eliminated),
+	}
+}
+
+// OfferToData converts a trade offer to its serialisable form.
+func OfferToData(o engine.TradeOffer) TradeOfferData {
+	return TradeOfferData{
+		FromPlayer:       o.FromPlayer,
+		ToPlayer:         o.ToPlayer,
+		OfferedProps:     o.OfferedProps,
+		WantedProps:      o.WantedProps,
+		OfferedMoney:     o.OfferedMoney,
+		WantedMoney:      o.WantedMoney,
+		OfferedJailCards: o.OfferedJailCards,
+		WantedJailCards:  o.WantedJailCards,
+	}
+}
+
+// DataToOffer converts a saved trade offer back to an engine.TradeOffer.
+func DataToOffer(d TradeOfferData) engine.TradeOffer {
+	return engine.TradeOffer{
+		FromPlayer:       d.FromPlayer,
+		ToPlayer:         d.ToPlayer,
+		OfferedProps:     d.OfferedProps,
+		WantedProps:      d.WantedProps,
+		OfferedMoney:     d.OfferedMoney,
+		WantedMoney:      d.WantedMoney,
+		OfferedJailCards: d.OfferedJailCards,
+		WantedJailCards:  d.WantedJailCards,
+	}
+}
+
+// deckToData records each card's index in the standard list. Identical
+// cards are matched in turn, so duplicates keep distinct indices.
+func deckToData(d *board.Deck, standard []board.Card) DeckData {
+	used := make([]bool, len(standard))
+	data := DeckData{Next: d.Current}
+	for _, c := range d.Cards {
+		for i, sc := range standard {
+			if !used[i] && sc == c {
+				used[i] = true
+				data.Order = append(data.Order, i)
+				break
+			}
+		}
+	}
+	return data
+}
+
+// dataToDeck puts a deck back in its saved order. A save whose order does
+// not match the standard list keeps the freshly shuffled deck.
+func dataToDeck(d *board.Deck, data DeckData, standard []board.Card) {
+	if len(data.Order) != len(standard) {
+		return
+	}
+	cards := make([]board.Card, len(standard))
+	for i, idx := range data.Order {
+		if idx < 0 || idx >= len(standard) {
+			return
+		}
+		cards[i] = standard[idx]
+	}
+	d.Cards = cards
+	d.Current = data.Next
+}
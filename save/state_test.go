@@ -0,0 +1,41 @@
+package save
+
+import (
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// newGame starts a standard game between n players.
+func newGame(n int) *engine.GameState {
+	var players []*player.Player
+	for i := 0; i < n; i++ {
+		players = append(players, player.NewPlayer(i, string(rune('A'+i)), false))
+	}
+	return engine.New(players, rng.New(42), engine.StandardRules())
+}
+
+func TestPublic(t *testing.T) {
+	s := newGame(3)
+	s.Players[0].Money = 900
+	s.Players[2].Money = 1100
+	data := FromState(s)
+	data.HiddenCash = true
+
+	pub := data.Public()
+	if pub.RNG != (RNGData{}) || len(pub.ChanceDeck.Order) > 0 || len(pub.CommunityDeck.Order) > 0 {
+		t.Error("public state gives away the dice or the cards to come")
+	}
+	// The client checks offers against the cash, and only masks it on screen
+	if pub.Players[0].Money != 900 || pub.Players[2].Money != 1100 || !pub.HiddenCash {
+		t.Error("public state changed the cash instead of marking it hidden")
+	}
+	if len(data.ChanceDeck.Order) == 0 || data.RNG.State == 0 {
+		t.Error("Public changed the state it was given")
+	}
+	if err := Validate(pub); err != nil {
+		t.Fatalf("public state does not validate: %v", err)
+	}
+}
@@ -0,0 +1,393 @@
+package save
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/config"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/player"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// ValidationError lists every inconsistency found in a save.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid save (%d problems): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// Validate checks a save for values out of range and for disagreements
+// between the player property lists, the board and the house/hotel pools.
+// It returns a *ValidationError describing every problem found.
+func Validate(d *SaveData) error {
+	v := &validator{data: d, board: board.NewBoard(rng.New(0))}
+	v.rules()
+	v.players()
+	v.properties()
+	v.pools()
+	v.turn()
+	v.decks()
+	v.stats()
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+type validator struct {
+	data     *SaveData
+	board    *board.Board
+	problems []string
+}
+
+func (v *validator) fail(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) validPlayer(id int) bool {
+	return id >= 0 && id < len(v.data.Players)
+}
+
+func (v *validator) rules() {
+	r := v.data.Rules
+	if r.StartingMoney <= 0 {
+		v.fail("rules.starting_money: %d", r.StartingMoney)
+	}
+	if r.GoSalary < 0 {
+		v.fail("rules.go_salary: %d", r.GoSalary)
+	}
+	if r.JailFine < 0 {
+		v.fail("rules.jail_fine: %d", r.JailFine)
+	}
+	if r.MaxJailTurns < 1 {
+		v.fail("rules.max_jail_turns: %d", r.MaxJailTurns)
+	}
+	if r.MortgageRate < 1 || r.MortgageRate > 100 {
+		v.fail("rules.mortgage_rate: %d%%", r.MortgageRate)
+	}
+	if r.InterestRate < 0 {
+		v.fail("rules.interest_rate: %d%%", r.InterestRate)
+	}
+	if v.data.Jackpot < 0 || (v.data.Jackpot > 0 && !r.FreeParkingJackpot) {
+		v.fail("jackpot: %d", v.data.Jackpot)
+	}
+}
+
+func (v *validator) players() {
+	d := v.data
+	if n := len(d.Players); n < 2 || n > config.MaxPlayers {
+		v.fail("players: %d players, want 2-%d", n, config.MaxPlayers)
+	}
+	colors := make(map[int]int)
+	for i, p := range d.Players {
+		if p.ID != i {
+			v.fail("players[%d]: id is %d", i, p.ID)
+		}
+		if p.Color < 0 || p.Color >= config.TokenColors {
+			v.fail("players[%d]: unknown colour %d", i, p.Color)
+		} else if other, taken := colors[p.Color]; taken {
+			v.fail("players[%d]: colour %d already taken by player %d", i, p.Color, other)
+		} else {
+			colors[p.Color] = i
+		}
+		if d := player.Difficulty(p.Difficulty); d < player.DifficultyNormal || d > player.DifficultyHard {
+			v.fail("players[%d]: unknown difficulty %d", i, p.Difficulty)
+		}
+		if p.Money < 0 {
+			v.fail("players[%d]: negative money %d", i, p.Money)
+		}
+		if p.Position < 0 || p.Position >= config.SpaceCount {
+			v.fail("players[%d]: position %d off the board", i, p.Position)
+		}
+		if p.JailTurns < 0 || p.JailTurns >= d.Rules.MaxJailTurns || (!p.InJail && p.JailTurns != 0) {
+			v.fail("players[%d]: %d jail turns (in jail: %t)", i, p.JailTurns, p.InJail)
+		}
+		if p.GetOutOfJailCards < 0 {
+			v.fail("players[%d]: negative jail cards %d", i, p.GetOutOfJailCards)
+		}
+		if p.Bankrupt && (len(p.Properties) > 0 || p.Money != 0) {
+			v.fail("players[%d]: bankrupt but still holds money or properties", i)
+		}
+
+		seen := make(map[int]bool)
+		for _, idx := range p.Properties {
+			switch {
+			case idx < 0 || idx >= config.SpaceCount:
+				v.fail("players[%d].properties: space %d off the board", i, idx)
+			case !v.board.IsProperty(idx):
+				v.fail("players[%d].properties: %s cannot be owned", i, v.board.Spaces[idx].Name)
+			case seen[idx]:
+				v.fail("players[%d].properties: %s listed twice", i, v.board.Spaces[idx].Name)
+			case d.Properties[idx].OwnerID != i:
+				v.fail("players[%d].properties: %s has owner_id %d", i, v.board.Spaces[idx].Name, d.Properties[idx].OwnerID)
+			}
+			seen[idx] = true
+		}
+	}
+	if !v.validPlayer(d.Current) {
+		v.fail("current: player %d does not exist", d.Current)
+	} else if phase := engine.TurnPhase(d.Turn.Phase); d.Players[d.Current].Bankrupt && phase != engine.PhasePostAction && phase != engine.PhaseAuction && phase != engine.PhaseTransfer {
+		// Only the turn in which they went bankrupt may still be theirs,
+		// while their lots are auctioned off or taken over
+		v.fail("current: player %d is bankrupt", d.Current)
+	}
+}
+
+func (v *validator) properties() {
+	d := v.data
+	for idx, prop := range d.Properties {
+		space := v.board.Spaces[idx]
+		if prop.OwnerID == -1 {
+			if prop.Houses != 0 || prop.Mortgaged {
+				v.fail("properties[%d] (%s): unowned but improved or mortgaged", idx, space.Name)
+			}
+			continue
+		}
+		if !v.board.IsProperty(idx) {
+			v.fail("properties[%d] (%s): cannot be owned, has owner_id %d", idx, space.Name, prop.OwnerID)
+			continue
+		}
+		if !v.validPlayer(prop.OwnerID) {
+			v.fail("properties[%d] (%s): owner_id %d is not a player", idx, space.Name, prop.OwnerID)
+			continue
+		}
+		if !contains(d.Players[prop.OwnerID].Properties, idx) {
+			v.fail("properties[%d] (%s): owner %d does not list it", idx, space.Name, prop.OwnerID)
+		}
+
+		if prop.Houses < 0 || prop.Houses > config.HotelLevel {
+			v.fail("properties[%d] (%s): %d houses", idx, space.Name, prop.Houses)
+		}
+		if prop.Houses == 0 {
+			continue
+		}
+		if space.Type != board.SpaceProperty {
+			v.fail("properties[%d] (%s): houses on a railroad or utility", idx, space.Name)
+		}
+		if prop.Mortgaged {
+			v.fail("properties[%d] (%s): houses on a mortgaged lot", idx, space.Name)
+		}
+		for _, other := range v.board.SpacesInGroup(space.Group) {
+			if d.Properties[other].OwnerID != prop.OwnerID {
+				v.fail("properties[%d] (%s): houses without owning %s", idx, space.Name, v.board.Spaces[other].Name)
+			}
+		}
+	}
+}
+
+func (v *validator) pools() {
+	d := v.data
+	houses, hotels := 0, 0
+	for _, prop := range d.Properties {
+		if prop.Houses == config.HotelLevel {
+			hotels++
+		} else if prop.Houses > 0 {
+			houses += prop.Houses
+		}
+	}
+	if d.HousePool < 0 || d.HousePool+houses != config.MaxHouses {
+		v.fail("house_pool: %d in pool + %d on board, want %d", d.HousePool, houses, config.MaxHouses)
+	}
+	if d.HotelPool < 0 || d.HotelPool+hotels != config.MaxHotels {
+		v.fail("hotel_pool: %d in pool + %d on board, want %d", d.HotelPool, hotels, config.MaxHotels)
+	}
+}
+
+func (v *validator) turn() {
+	d := v.data
+	phase := engine.TurnPhase(d.Turn.Phase)
+	if phase < engine.PhasePreRoll || phase > engine.PhaseTransfer {
+		v.fail("turn.phase: unknown phase %d", d.Turn.Phase)
+	}
+	if d.Turn.Round < 1 {
+		v.fail("turn.round: %d", d.Turn.Round)
+	}
+	if d.Turn.DoublesCount < 0 || d.Turn.DoublesCount > 3 {
+		v.fail("turn.doubles_count: %d", d.Turn.DoublesCount)
+	}
+	if d.Turn.Trades < 0 {
+		v.fail("turn.trades_proposed: %d", d.Turn.Trades)
+	}
+	if d.Die1 < 0 || d.Die1 > 6 || d.Die2 < 0 || d.Die2 > 6 {
+		v.fail("dice: %d and %d", d.Die1, d.Die2)
+	}
+
+	if (phase == engine.PhaseAuction) != (d.Auction != nil) {
+		v.fail("auction: present=%t in phase %s", d.Auction != nil, phase)
+	}
+	if a := d.Auction; a != nil {
+		if !v.board.IsProperty(a.SpaceIdx) || d.Properties[a.SpaceIdx].OwnerID != -1 {
+			v.fail("auction.space_idx: %d is not an unowned property", a.SpaceIdx)
+		}
+		if !v.validPlayer(a.Current) || !a.Active[a.Current] {
+			v.fail("auction.current: player %d is not bidding", a.Current)
+		}
+		if a.HighBidder != -1 && !v.validPlayer(a.HighBidder) {
+			v.fail("auction.high_bidder: player %d does not exist", a.HighBidder)
+		}
+		if a.HighBid < 0 {
+			v.fail("auction.high_bid: negative bid %d", a.HighBid)
+		}
+		if !resumable(engine.TurnPhase(a.ReturnPhase)) {
+			v.fail("auction.return_phase: %s", engine.TurnPhase(a.ReturnPhase))
+		}
+	}
+
+	// Lots handed back to the bank wait through debts, trades and
+	// transfers until they can be auctioned
+	queued := map[int]bool{}
+	if d.Auction != nil {
+		queued[d.Auction.SpaceIdx] = true
+	}
+	for _, idx := range d.AuctionQueue {
+		if idx < 0 || idx >= config.SpaceCount || !v.board.IsProperty(idx) || d.Properties[idx].OwnerID != -1 || queued[idx] {
+			v.fail("auction_queue: %d is not a distinct unowned property", idx)
+			continue
+		}
+		queued[idx] = true
+	}
+	if len(d.AuctionQueue) > 0 && d.Rules.NoAuctions {
+		v.fail("auction_queue: lots queued without auctions")
+	}
+
+	if (phase == engine.PhaseTradeResponse) != (d.PendingTrade != nil) {
+		v.fail("pending_trade: present=%t in phase %s", d.PendingTrade != nil, phase)
+	}
+	if t := d.PendingTrade; t != nil {
+		o := t.Offer
+		if !v.validPlayer(o.FromPlayer) || !v.validPlayer(o.ToPlayer) || o.FromPlayer == o.ToPlayer {
+			v.fail("pending_trade.offer: players %d and %d", o.FromPlayer, o.ToPlayer)
+		} else {
+			for _, idx := range o.OfferedProps {
+				if idx < 0 || idx >= config.SpaceCount || d.Properties[idx].OwnerID != o.FromPlayer {
+					v.fail("pending_trade.offer: offered space %d not owned by player %d", idx, o.FromPlayer)
+				}
+			}
+			for _, idx := range o.WantedProps {
+				if idx < 0 || idx >= config.SpaceCount || d.Properties[idx].OwnerID != o.ToPlayer {
+					v.fail("pending_trade.offer: wanted space %d not owned by player %d", idx, o.ToPlayer)
+				}
+			}
+		}
+		if rp := engine.TurnPhase(t.ReturnPhase); rp != engine.PhasePreRoll && rp != engine.PhasePostAction && rp != engine.PhaseDebt {
+			v.fail("pending_trade.return_phase: %s", rp)
+		}
+	}
+
+	// A debtor may be mid-trade to raise the cash, and debts wait while
+	// mortgaged lots received are decided on
+	inDebt := phase == engine.PhaseDebt ||
+		phase == engine.PhaseTradeResponse && d.PendingTrade != nil && engine.TurnPhase(d.PendingTrade.ReturnPhase) == engine.PhaseDebt
+	if phase != engine.PhaseTransfer && inDebt != (d.Debts != nil) {
+		v.fail("debts: present=%t in phase %s", d.Debts != nil, phase)
+	}
+	if debts := d.Debts; debts != nil {
+		if len(debts.Owed) == 0 {
+			v.fail("debts.owed: empty")
+		}
+		for i, debt := range debts.Owed {
+			if !v.validPlayer(debt.Debtor) || d.Players[debt.Debtor].Bankrupt {
+				v.fail("debts.owed[%d]: debtor %d cannot pay", i, debt.Debtor)
+			}
+			if debt.Creditor != engine.Bank && debt.Creditor != engine.FreeParking &&
+				(!v.validPlayer(debt.Creditor) || debt.Creditor == debt.Debtor) {
+				v.fail("debts.owed[%d]: creditor %d", i, debt.Creditor)
+			}
+			if debt.Amount <= 0 {
+				v.fail("debts.owed[%d]: amount %d", i, debt.Amount)
+			}
+			if debt.Rent && (debt.Space < 0 || debt.Space >= config.SpaceCount || !v.board.IsProperty(debt.Space)) {
+				v.fail("debts.owed[%d]: rent for space %d", i, debt.Space)
+			}
+		}
+		if !resumable(engine.TurnPhase(debts.ReturnPhase)) {
+			v.fail("debts.return_phase: %s", engine.TurnPhase(debts.ReturnPhase))
+		}
+	}
+
+	if (phase == engine.PhaseTransfer) != (d.Transfers != nil) {
+		v.fail("transfers: present=%t in phase %s", d.Transfers != nil, phase)
+	}
+	if t := d.Transfers; t != nil {
+		if len(t.Pending) == 0 {
+			v.fail("transfers.pending: empty")
+		}
+		for i, tr := range t.Pending {
+			switch {
+			case !v.validPlayer(tr.Player) || d.Players[tr.Player].Bankrupt:
+				v.fail("transfers.pending[%d]: player %d cannot receive", i, tr.Player)
+			case tr.Space < 0 || tr.Space >= config.SpaceCount || d.Properties[tr.Space].OwnerID != tr.Player:
+				v.fail("transfers.pending[%d]: space %d not owned by player %d", i, tr.Space, tr.Player)
+			case !d.Properties[tr.Space].Mortgaged:
+				v.fail("transfers.pending[%d]: %s is not mortgaged", i, v.board.Spaces[tr.Space].Name)
+			}
+		}
+		if rp := engine.TurnPhase(t.ReturnPhase); rp != engine.PhaseDebt && !resumable(rp) {
+			v.fail("transfers.return_phase: %s", rp)
+		}
+	}
+}
+
+// resumable reports whether play can pick up in phase p after a debt.
+func resumable(p engine.TurnPhase) bool {
+	switch p {
+	case engine.PhasePreRoll, engine.PhaseBuyDecision, engine.PhaseIncomeTax, engine.PhasePostAction:
+		return true
+	}
+	return false
+}
+
+func (v *validator) decks() {
+	v.deck("chance_deck", v.data.ChanceDeck, len(board.ChanceCards()))
+	v.deck("community_deck", v.data.CommunityDeck, len(board.CommunityChestCards()))
+}
+
+// deck checks a saved deck order is a permutation of the standard cards.
+// An empty order (from a migrated save) means a fresh shuffle.
+func (v *validator) deck(name string, d DeckData, size int) {
+	if len(d.Order) == 0 {
+		return
+	}
+	if len(d.Order) != size {
+		v.fail("%s.order: %d cards, want %d", name, len(d.Order), size)
+		return
+	}
+	seen := make([]bool, size)
+	for _, idx := range d.Order {
+		if idx < 0 || idx >= size || seen[idx] {
+			v.fail("%s.order: not a permutation of the deck", name)
+			return
+		}
+		seen[idx] = true
+	}
+	if d.Next < 0 || d.Next >= size {
+		v.fail("%s.next: %d out of range", name, d.Next)
+	}
+}
+
+func contains(list []int, x int) bool {
+	for _, v := range list {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) stats() {
+	st := v.data.Stats
+	if st.Turns < 0 || st.Rolls < 0 || st.Trades < 0 || st.Doubles < 0 || st.Doubles > st.Rolls {
+		v.fail("stats: %d turns, %d rolls, %d doubles, %d trades", st.Turns, st.Rolls, st.Doubles, st.Trades)
+	}
+	seen := make(map[int]bool)
+	for _, id := range st.Eliminated {
+		if !v.validPlayer(id) || !v.data.Players[id].Bankrupt || seen[id] {
+			v.fail("stats.eliminated: player %d", id)
+		}
+		seen[id] = true
+	}
+}
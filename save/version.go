@@ -0,0 +1,200 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+	"github.com/AchrafSoltani/MoroccanMonopoly/rng"
+)
+
+// Version is the save format written by this build.
+//
+//	1: players, board and dice only (files without a version field)
+//	2: turn phase, decks, auction, pending trade, RNG and dialog
+//	3: slot metadata and round number
+//	4: house rules and the Free Parking jackpot
+//	5: token colours
+//	6: the phase an auction returns to; the auction queue on its own
+//	7: jail fine, jail turns and mortgage rates in the rules
+//	8: game statistics
+const Version = 8
+
+// migration upgrades a raw save from one version to the next.
+type migration func(raw map[string]any) error
+
+// migrations[v] upgrades a version v save to version v+1.
+var migrations = map[int]migration{
+	1: migrateV1,
+	2: migrateV2,
+	3: migrateV3,
+	4: migrateV4,
+	5: migrateV5,
+	6: migrateV6,
+	7: migrateV7,
+}
+
+// decode reads a save of any supported version, upgrading it to Version.
+func decode(jsonData []byte) (*SaveData, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(jsonData, &header); err != nil {
+		return nil, err
+	}
+	version := header.Version
+	if version == 0 {
+		version = 1 // written before the field existed
+	}
+	if version > Version {
+		return nil, fmt.Errorf("save version %d is newer than supported version %d", version, Version)
+	}
+
+	if version < Version {
+		// Migrations work on the generic JSON tree; numbers stay exact
+		var raw map[string]any
+		dec := json.NewDecoder(bytes.NewReader(jsonData))
+		dec.UseNumber()
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		for ; version < Version; version++ {
+			migrate, ok := migrations[version]
+			if !ok {
+				return nil, fmt.Errorf("no migration from save version %d", version)
+			}
+			if err := migrate(raw); err != nil {
+				return nil, fmt.Errorf("migrating save from version %d: %w", version, err)
+			}
+		}
+		raw["version"] = Version
+
+		upgraded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		jsonData = upgraded
+	}
+
+	data := &SaveData{}
+	if err := json.Unmarshal(jsonData, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// migrateV1 adds the turn state missing from version 1. Those saves always
+// resumed at the start of the current player's turn, so that is where the
+// game picks up; the decks keep a fresh shuffle from a new seed.
+func migrateV1(raw map[string]any) error {
+	phase := engine.PhasePreRoll
+	players, _ := raw["players"].([]any)
+	n, _ := raw["current"].(json.Number)
+	current, _ := n.Int64()
+	if i := int(current); i >= 0 && i < len(players) {
+		if p, ok := players[i].(map[string]any); ok && p["in_jail"] == true {
+			phase = engine.PhaseJailDecision
+		}
+	}
+	raw["turn"] = map[string]any{"phase": int(phase)}
+
+	seed := rng.NewSeed()
+	raw["rng"] = map[string]any{"seed": seed, "state": uint64(seed)}
+	return nil
+}
+
+// migrateV2 adds the round counter and slot metadata. The round a version 2
+// game had reached is unknown, so it restarts at 1; net worth in the
+// metadata is approximated by cash until the game is saved again.
+func migrateV2(raw map[string]any) error {
+	if turn, ok := raw["turn"].(map[string]any); ok {
+		turn["round"] = 1
+	}
+
+	var players []map[string]any
+	list, _ := raw["players"].([]any)
+	for _, item := range list {
+		p, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		players = append(players, map[string]any{
+			"name":      p["name"],
+			"is_ai":     p["is_ai"],
+			"net_worth": p["money"],
+			"bankrupt":  p["bankrupt"],
+		})
+	}
+	raw["meta"] = map[string]any{"round": 1, "players": players}
+	return nil
+}
+
+// migrateV3 adds the rule set. Games before version 4 were all played by
+// the standard rules.
+func migrateV3(raw map[string]any) error {
+	raw["rules"] = RulesToData(engine.StandardRules())
+	return nil
+}
+
+// migrateV4 adds token colours, which used to follow the seat.
+func migrateV4(raw map[string]any) error {
+	players, _ := raw["players"].([]any)
+	for i, item := range players {
+		if p, ok := item.(map[string]any); ok {
+			p["color"] = i
+		}
+	}
+	return nil
+}
+
+// migrateV5 adds the phase an auction in progress returns to, and moves
+// the queue of bank lots out of the auction. Auctions always used to end
+// the turn's action, so that is where it picks up. A queue was only saved
+// during an auction; one pending in another phase was lost.
+func migrateV5(raw map[string]any) error {
+	if auction, ok := raw["auction"].(map[string]any); ok {
+		auction["return_phase"] = int(engine.PhasePostAction)
+		if queue, ok := auction["queue"]; ok {
+			raw["auction_queue"] = queue
+			delete(auction, "queue")
+		}
+	}
+	return nil
+}
+
+// migrateV6 adds the jail and mortgage terms to the rules. They were fixed
+// at the standard values until version 7.
+func migrateV6(raw map[string]any) error {
+	rules, ok := raw["rules"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	standard := engine.StandardRules()
+	for key, value := range map[string]int{
+		"jail_fine":      standard.JailFine,
+		"max_jail_turns": standard.MaxJailTurns,
+		"mortgage_rate":  standard.MortgageRate,
+		"interest_rate":  standard.InterestRate,
+	} {
+		if _, ok := rules[key]; !ok {
+			rules[key] = value
+		}
+	}
+	return nil
+}
+
+// migrateV7 adds the game statistics. What happened before the save is not
+// known beyond who went bankrupt, listed in seat order, so the counts start
+// again from zero.
+func migrateV7(raw map[string]any) error {
+	var eliminated []int
+	players, _ := raw["players"].([]any)
+	for i, item := range players {
+		if p, ok := item.(map[string]any); ok && p["bankrupt"] == true {
+			eliminated = append(eliminated, i)
+		}
+	}
+	raw["stats"] = StatsData{Eliminated: eliminated}
+	return nil
+}
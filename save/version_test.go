@@ -0,0 +1,188 @@
+package save
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/AchrafSoltani/MoroccanMonopoly/board"
+	"github.com/AchrafSoltani/MoroccanMonopoly/engine"
+)
+
+// fixtures are saves as each version of the format wrote them, with what
+// migrating them must have kept or filled in.
+var fixtures = []struct {
+	file  string
+	check func(t *testing.T, d *SaveData, s *engine.GameState)
+}{
+	{"v1.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		if s.Phase != engine.PhaseJailDecision || s.Current != 1 {
+			t.Errorf("resumes player %d in %s, want the jailed player deciding", s.Current, s.Phase)
+		}
+		if d.Turn.Round != 1 || d.Meta.Round != 1 || len(d.Meta.Players) != 2 {
+			t.Errorf("round %d, metadata %+v", d.Turn.Round, d.Meta)
+		}
+		if s.Board.Properties[1].Houses != 1 || s.Board.HousePool != 30 {
+			t.Error("houses lost in migration")
+		}
+	}},
+	{"v2.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		if s.Phase != engine.PhaseAuction || s.AuctionReturnPhase != engine.PhasePostAction {
+			t.Errorf("auction in %s returns to %s", s.Phase, s.AuctionReturnPhase)
+		}
+		if s.Actor() != 1 || s.AuctionHighBid != 40 || s.AuctionHighBidder != 0 {
+			t.Error("the auction did not pick up where it was")
+		}
+		if deck := s.Board.ChanceDeck; deck.Current != 5 || deck.Cards[5] != board.ChanceCards()[12] {
+			t.Error("chance deck order lost")
+		}
+		if s.Rand.Seed() != 7 || s.Rand.State() != 123456789 {
+			t.Error("random source lost")
+		}
+	}},
+	{"v3.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		if s.Phase != engine.PhaseTradeResponse || s.Actor() != 1 || s.TradeReturnPhase != engine.PhasePreRoll {
+			t.Errorf("waiting on player %d in %s", s.Actor(), s.Phase)
+		}
+		if s.Round != 7 || s.TradesProposed != 1 || d.Meta.Name != "Partie du soir" {
+			t.Error("round, trades or slot name lost")
+		}
+		if !s.Rules.IsStandard() {
+			t.Error("an old save did not get the standard rules")
+		}
+	}},
+	{"v4.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		if !s.Rules.FreeParkingJackpot || !s.Rules.EvenBuild || s.Rules.StartingMoney != 2000 || s.Jackpot != 150 {
+			t.Errorf("rules %+v, jackpot %d", s.Rules, s.Jackpot)
+		}
+		for i, p := range s.Players {
+			if p.Color != i {
+				t.Errorf("player %d has colour %d, want the seat's", i, p.Color)
+			}
+		}
+	}},
+	{"v5.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		if s.AuctionReturnPhase != engine.PhasePostAction {
+			t.Errorf("auction returns to %s", s.AuctionReturnPhase)
+		}
+		if !reflect.DeepEqual(s.AuctionQueue, []int{9, 12}) {
+			t.Errorf("queue %v, want the lots the auction held", s.AuctionQueue)
+		}
+		if s.Players[0].Color != 3 || s.Players[2].Color != 1 {
+			t.Error("colours lost")
+		}
+		if !reflect.DeepEqual(d.Stats.Eliminated, []int{2}) || d.Stats.Turns != 0 {
+			t.Errorf("statistics %+v, want only the bankrupt player", d.Stats)
+		}
+	}},
+	{"v6.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		if s.Phase != engine.PhaseDebt || s.Actor() != 1 || s.DebtReturnPhase != engine.PhasePostAction {
+			t.Errorf("waiting on player %d in %s", s.Actor(), s.Phase)
+		}
+		want := engine.Debt{Debtor: 1, Creditor: 0, Amount: 600, Rent: true, Space: 39}
+		if len(s.Debts) != 1 || s.Debts[0] != want {
+			t.Errorf("debts %+v, want %+v", s.Debts, want)
+		}
+		if !reflect.DeepEqual(s.AuctionQueue, []int{12}) || !d.HiddenCash {
+			t.Error("queue or hidden cash lost")
+		}
+		if s.Rand.State() != 18446744073709551615 {
+			t.Errorf("random state %d not kept exactly", s.Rand.State())
+		}
+		if r, std := s.Rules, engine.StandardRules(); r.JailFine != std.JailFine || r.MaxJailTurns != std.MaxJailTurns ||
+			r.MortgageRate != std.MortgageRate || r.InterestRate != std.InterestRate {
+			t.Errorf("jail and mortgage terms %+v, want the standard ones", r)
+		}
+	}},
+	{"v7.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		if s.Rules.JailFine != 100 || s.Rules.MaxJailTurns != 4 || s.Players[2].JailTurns != 3 {
+			t.Errorf("jail fine %d, %d turns; player in jail for %d", s.Rules.JailFine, s.Rules.MaxJailTurns, s.Players[2].JailTurns)
+		}
+		if s.MortgageValue(39) != 160 || s.UnmortgageCost(39) != 192 || s.TransferInterest(39) != 32 {
+			t.Error("mortgage terms not applied")
+		}
+	}},
+	{"v8.json", func(t *testing.T, d *SaveData, s *engine.GameState) {
+		st := DataToStats(d.Stats)
+		if st.Turns != 87 || st.Doubles != 14 || st.Landings[10] != 9 || st.RentReceived[0] != 1810 || st.JailVisits[1] != 4 {
+			t.Errorf("statistics %+v not kept", st)
+		}
+	}},
+}
+
+func TestFixtures(t *testing.T) {
+	if n := len(fixtures); n != Version {
+		t.Fatalf("%d fixtures for %d versions", n, Version)
+	}
+	for _, f := range fixtures {
+		t.Run(f.file, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata", f.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := decode(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Version != Version {
+				t.Errorf("version %d after migration", d.Version)
+			}
+			if err := Validate(d); err != nil {
+				t.Fatal(err)
+			}
+			s := d.Restore()
+			f.check(t, d, s)
+			roundTrip(t, d, s)
+		})
+	}
+}
+
+// roundTrip checks that saving s, restored from d, gives back the state d
+// recorded, and that the save reads back as the same.
+func roundTrip(t *testing.T, d *SaveData, s *engine.GameState) {
+	t.Helper()
+	again := FromState(s)
+	if err := Validate(again); err != nil {
+		t.Fatalf("restored game saves invalid: %v", err)
+	}
+	want, got := reflect.ValueOf(gameOf(d, d)), reflect.ValueOf(gameOf(again, d))
+	for i := 0; i < want.NumField(); i++ {
+		if w, g := want.Field(i).Interface(), got.Field(i).Interface(); !reflect.DeepEqual(g, w) {
+			t.Errorf("%s saved again as %+v, want %+v", want.Type().Field(i).Name, g, w)
+		}
+	}
+
+	raw, err := json.Marshal(again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, again) {
+		t.Error("save does not read back as written")
+	}
+}
+
+// gameOf is the part of d the engine restores, leaving out the metadata,
+// presentation and statistics, and any deck whose order recorded does not
+// give.
+func gameOf(d, recorded *SaveData) SaveData {
+	g := *d
+	g.Meta = SlotMeta{}
+	g.Messages = nil
+	g.Stats = StatsData{}
+	g.Dialog = 0
+	g.TradeBuilder = nil
+	g.HandOff, g.HiddenCash = false, false
+	if len(recorded.ChanceDeck.Order) == 0 {
+		g.ChanceDeck = DeckData{}
+	}
+	if len(recorded.CommunityDeck.Order) == 0 {
+		g.CommunityDeck = DeckData{}
+	}
+	return g
+}